@@ -1,13 +1,19 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
+	"github.com/lehaisonagentai3/free-contest/backend/internal/auth"
 	"github.com/lehaisonagentai3/free-contest/backend/internal/config"
 	"github.com/lehaisonagentai3/free-contest/backend/internal/controller"
 	"github.com/lehaisonagentai3/free-contest/backend/internal/service"
@@ -30,6 +36,11 @@ import (
 // @license.url http://www.apache.org/licenses/LICENSE-2.0.html
 
 // @host localhost:8298
+
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
+// @description Session token from /api/v1/auth/login, sent as "Bearer <token>"
 func main() {
 	gin.SetMode(gin.ReleaseMode)
 	// Load configuration
@@ -50,23 +61,45 @@ func main() {
 	fmt.Printf("Contest Name: %s\n", contest.Name)
 	fmt.Printf("Contest Folder Path: %s\n", contest.FolderPath)
 	fmt.Println("Total Subjects:", len(contest.Subjects))
+	fmt.Printf("Data File: %s\n", conf.DataPath)
+	fmt.Printf("Accommodations File: %s\n", conf.AccommodationPath)
 	fmt.Println("Contest service initialized successfully!")
 
+	// Session tokens
+	secret := []byte(conf.TokenSecret)
+	if len(secret) == 0 {
+		secret, err = auth.RandomSecret()
+		if err != nil {
+			log.Fatalf("Failed to generate token secret: %v", err)
+		}
+		log.Println("Warning: token_secret is not set, sessions will not survive a restart")
+	}
+	signer := auth.NewSigner(secret, time.Duration(conf.TokenTTL)*time.Minute)
+	loginGuard := auth.NewLoginGuard(conf.LoginLimit.MaxFailures, conf.LoginLimit.MaxAddressFailures, time.Duration(conf.LoginLimit.Lockout)*time.Minute)
+
 	// Initialize controllers
 	testController := controller.NewTestController(contestService)
 	unitController := controller.NewUnitController(contestService)
 	officerController := controller.NewOfficerController(contestService)
 	subjectController := controller.NewSubjectController(contestService)
+	adminController := controller.NewAdminController(contestService, signer, loginGuard)
+	authController := controller.NewAuthController(contestService, signer, loginGuard)
+	clockController := controller.NewClockController(contestService)
 
 	// Initialize Gin router
-	router := gin.Default()
+	router := gin.New()
+	// Login limits are counted per client address, which must not be spoofable
+	if err := router.SetTrustedProxies(conf.TrustedProxies); err != nil {
+		log.Fatalf("Invalid trusted_proxies: %v", err)
+	}
+	router.Use(gin.Logger(), controller.RequestID(), controller.ErrorHandler(), controller.Recovery())
 
 	// Configure CORS middleware
 	router.Use(cors.New(cors.Config{
 		AllowOrigins:     []string{"*"}, // In production, specify exact origins
 		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization", "X-Requested-With"},
-		ExposeHeaders:    []string{"Content-Length"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization", "X-Requested-With", "X-Request-ID", "Idempotency-Key"},
+		ExposeHeaders:    []string{"Content-Length", "X-Request-ID", "Retry-After"},
 		AllowCredentials: true,
 		MaxAge:           12 * time.Hour,
 	}))
@@ -79,12 +112,12 @@ func main() {
 	router.NoRoute(func(c *gin.Context) {
 		// Check if the request is for an API route
 		if len(c.Request.URL.Path) >= 4 && c.Request.URL.Path[:4] == "/api" {
-			c.JSON(http.StatusNotFound, gin.H{"error": "API endpoint not found"})
+			controller.RouteNotFound(c)
 			return
 		}
 		// Check if the request is for swagger
 		if len(c.Request.URL.Path) >= 4 && c.Request.URL.Path[:4] == "/v1/" {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Route not found"})
+			controller.RouteNotFound(c)
 			return
 		}
 		// Serve React app index.html for all other routes (SPA routing)
@@ -94,11 +127,17 @@ func main() {
 	// API routes
 	v1 := router.Group("/api/v1")
 	{
-		tests := v1.Group("/tests")
+		v1.POST("/auth/login", authController.Login)
+		v1.GET("/time", clockController.GetServerTime)
+
+		tests := v1.Group("/tests", controller.RoleRequired(signer, auth.RoleOfficer))
 		{
 			tests.GET("/officer-subject", testController.GetSubjectTestForOfficer)
 			tests.POST("/start", testController.StartTest)
+			tests.GET("/questions", testController.GetTestQuestions)
 			tests.POST("/submit", testController.SubmitTest)
+			tests.PUT("/answers", testController.SaveAnswers)
+			tests.GET("/heartbeat", clockController.Heartbeat)
 		}
 
 		// Units routes
@@ -110,6 +149,27 @@ func main() {
 
 		// Subjects routes
 		v1.GET("/subjects", subjectController.GetAllSubjects)
+
+		// Admin routes, the only ones exposing the answer key
+		v1.POST("/admin/login", adminController.Login)
+		admin := v1.Group("/admin", controller.RoleRequired(signer, auth.RoleAdmin))
+		{
+			admin.GET("/tests/officer-subject", adminController.GetOfficerSubjectTest)
+			admin.GET("/tests/in-progress", adminController.ListInProgressTests)
+			admin.GET("/tests/:id/audit", adminController.AuditTest)
+			admin.POST("/tests/:id/invalidate", adminController.InvalidateTest)
+			admin.POST("/tests/:id/extend", adminController.ExtendTest)
+			admin.POST("/tests/:id/pause", adminController.PauseTest)
+			admin.POST("/tests/:id/resume", adminController.ResumeTest)
+			admin.POST("/tests/:id/reset", adminController.ResetTest)
+			admin.POST("/submissions/:id/void", adminController.VoidSubmission)
+			admin.GET("/audit-trail", adminController.ListAdminActions)
+			admin.GET("/accommodations", adminController.ListAccommodations)
+			admin.PUT("/accommodations", adminController.SetAccommodation)
+			admin.DELETE("/accommodations", adminController.DeleteAccommodation)
+			admin.GET("/question-bank/validation", adminController.ValidateQuestionBank)
+			admin.GET("/officers/:id/submissions", adminController.GetOfficerSubmissions)
+		}
 	}
 
 	// Swagger documentation route
@@ -127,7 +187,30 @@ func main() {
 	log.Println("Starting server on port 8298...")
 	log.Println("Swagger documentation available at: http://localhost:8298/v1/api/free-contest/swagger/index.html")
 
-	if err := router.Run(":8298"); err != nil {
+	server := &http.Server{Addr: ":8298", Handler: router}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- server.ListenAndServe()
+	}()
+
+	select {
+	case err := <-serveErr:
+		contestService.Close()
 		log.Fatalf("Failed to start server: %v", err)
+	case <-ctx.Done():
+	}
+
+	// Finish the requests in flight, then stop auto-submitting and close the
+	// data file so that nothing is lost
+	log.Println("Shutting down...")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		log.Printf("Failed to shut down server: %v", err)
+	}
+	if err := contestService.Close(); err != nil {
+		log.Printf("Failed to close contest service: %v", err)
 	}
 }
@@ -0,0 +1,296 @@
+// Command contestctl checks and inspects a contest offline, with the same
+// loaders the server uses at startup.
+//
+//	contestctl validate [-config config.json] [-contest DIR] [-officers FILE] [-json]
+//	contestctl inspect  [-config config.json] [-contest DIR] [-json]
+//	contestctl sample   [-config config.json] [-contest DIR] -subject ID [-seed N] [-answers] [-json]
+//
+// The contest folder and officer roster default to contest_path and
+// officer_path of the config file.
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"os"
+	"text/tabwriter"
+
+	"github.com/lehaisonagentai3/free-contest/backend/internal/config"
+	"github.com/lehaisonagentai3/free-contest/backend/internal/model"
+	"github.com/lehaisonagentai3/free-contest/backend/internal/service"
+	"github.com/lehaisonagentai3/free-contest/backend/internal/utils"
+)
+
+// Exit codes
+const (
+	exitOK       = 0
+	exitProblems = 1 // the contest or roster has errors
+	exitUsage    = 2
+)
+
+const usage = `Usage: contestctl <command> [flags]
+
+Commands:
+  validate  check the question bank and the officer roster, exits 1 on errors
+  inspect   print question counts and test times per subject and chapter
+  sample    print a test generated for a subject
+
+Run "contestctl <command> -h" for the flags of a command.
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(exitUsage)
+	}
+	commands := map[string]func([]string) int{
+		"validate": runValidate,
+		"inspect":  runInspect,
+		"sample":   runSample,
+	}
+	command, ok := commands[os.Args[1]]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n%s", os.Args[1], usage)
+		os.Exit(exitUsage)
+	}
+	os.Exit(command(os.Args[2:]))
+}
+
+// paths are the inputs shared by every command
+type paths struct {
+	config   string
+	contest  string
+	officers string
+}
+
+func (p *paths) register(flags *flag.FlagSet, withOfficers bool) {
+	flags.StringVar(&p.config, "config", "config.json", "config file giving the default contest folder and officer roster")
+	flags.StringVar(&p.contest, "contest", "", "contest folder, overrides contest_path of the config")
+	if withOfficers {
+		flags.StringVar(&p.officers, "officers", "", "officer roster, overrides officer_path of the config")
+	}
+}
+
+// resolve fills the paths not given on the command line from the config
+// file, which is optional when they all are
+func (p *paths) resolve(withOfficers bool) error {
+	if p.contest != "" && (p.officers != "" || !withOfficers) {
+		return nil
+	}
+	conf, err := config.LoadAppConfig(p.config)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	if p.contest == "" {
+		p.contest = conf.ContestPath
+	}
+	if withOfficers && p.officers == "" {
+		p.officers = conf.OfficerPath
+	}
+	if p.contest == "" {
+		return errors.New("no contest folder, set -contest or contest_path in the config")
+	}
+	return nil
+}
+
+func runValidate(args []string) int {
+	flags := flag.NewFlagSet("validate", flag.ExitOnError)
+	var p paths
+	p.register(flags, true)
+	asJSON := flags.Bool("json", false, "write the report as JSON")
+	flags.Parse(args)
+	if err := p.resolve(true); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitUsage
+	}
+
+	_, report := utils.ValidateContest(p.contest)
+	if p.officers != "" {
+		report.ValidateOfficers(p.officers)
+	}
+	write := report.WriteText
+	if *asJSON {
+		write = report.WriteJSON
+	}
+	if err := write(os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitUsage
+	}
+	if report.HasErrors() {
+		return exitProblems
+	}
+	return exitOK
+}
+
+// chapterStats and subjectStats are what inspect prints
+type chapterStats struct {
+	ID              int    `json:"id"`
+	Name            string `json:"name"`
+	Questions       int    `json:"questions"`
+	NumQuestionTest int    `json:"num_question_test"`
+}
+
+type subjectStats struct {
+	ID              int             `json:"id"`
+	Name            string          `json:"name"`
+	TestTime        int             `json:"test_time"`
+	Questions       int             `json:"questions"`
+	NumQuestionTest int             `json:"num_question_test"`
+	Chapters        []*chapterStats `json:"chapters"`
+}
+
+func runInspect(args []string) int {
+	flags := flag.NewFlagSet("inspect", flag.ExitOnError)
+	var p paths
+	p.register(flags, false)
+	asJSON := flags.Bool("json", false, "write the statistics as JSON")
+	flags.Parse(args)
+	if err := p.resolve(false); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitUsage
+	}
+	contest, err := utils.LoadContestInfo(p.contest)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitProblems
+	}
+
+	var stats []*subjectStats
+	for _, subject := range contest.Subjects {
+		subjectStat := &subjectStats{
+			ID:              subject.ID,
+			Name:            subject.Name,
+			TestTime:        subject.TestTime,
+			NumQuestionTest: subject.NumQuestionTest,
+		}
+		for _, chapter := range subject.Chapters {
+			subjectStat.Questions += len(chapter.Questions)
+			subjectStat.Chapters = append(subjectStat.Chapters, &chapterStats{
+				ID:              chapter.ID,
+				Name:            chapter.Name,
+				Questions:       len(chapter.Questions),
+				NumQuestionTest: chapter.NumQuestionTest,
+			})
+		}
+		stats = append(stats, subjectStat)
+	}
+
+	if *asJSON {
+		return writeJSON(os.Stdout, stats)
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tSUBJECT / CHAPTER\tQUESTIONS\tIN TEST\tTEST TIME")
+	for _, subject := range stats {
+		fmt.Fprintf(w, "%d\t%s\t%d\t%d\t%d min\n", subject.ID, subject.Name, subject.Questions, subject.NumQuestionTest, subject.TestTime)
+		for _, chapter := range subject.Chapters {
+			fmt.Fprintf(w, "%d\t  %s\t%d\t%d\t\n", chapter.ID, chapter.Name, chapter.Questions, chapter.NumQuestionTest)
+		}
+	}
+	w.Flush()
+	return exitOK
+}
+
+// sampleQuestion is a question of a sampled test as the candidate sees it:
+// the options in displayed order and the correct one by its displayed letter
+type sampleQuestion struct {
+	ID      int    `json:"id"`
+	Content string `json:"content"`
+	AnswerA string `json:"answer_a,omitempty"`
+	AnswerB string `json:"answer_b,omitempty"`
+	AnswerC string `json:"answer_c,omitempty"`
+	AnswerD string `json:"answer_d,omitempty"`
+	Correct string `json:"correct,omitempty"`
+	// OptionOrder[i] is the bank letter of the option displayed at position i
+	OptionOrder []string `json:"option_order,omitempty"`
+}
+
+func runSample(args []string) int {
+	flags := flag.NewFlagSet("sample", flag.ExitOnError)
+	var p paths
+	p.register(flags, false)
+	subjectID := flags.Int("subject", 0, "ID of the subject, as listed by inspect")
+	seed := flags.Uint64("seed", 0, "seed of the test, a random one when 0")
+	answers := flags.Bool("answers", false, "show the correct option of every question")
+	asJSON := flags.Bool("json", false, "write the test as JSON")
+	flags.Parse(args)
+	if err := p.resolve(false); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitUsage
+	}
+	contest, err := utils.LoadContestInfo(p.contest)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitProblems
+	}
+
+	var subject *model.Subject
+	for _, s := range contest.Subjects {
+		if s.ID == *subjectID {
+			subject = s
+		}
+	}
+	if subject == nil {
+		fmt.Fprintf(os.Stderr, "no subject with ID %d, see contestctl inspect\n", *subjectID)
+		return exitUsage
+	}
+	if *seed == 0 {
+		*seed = rand.Uint64()
+	}
+	questions, err := service.GeneratePaper(subject, *seed)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitProblems
+	}
+
+	if *asJSON {
+		sample := make([]*sampleQuestion, 0, len(questions))
+		for _, question := range questions {
+			q := &sampleQuestion{
+				ID:          question.ID,
+				Content:     question.Content,
+				AnswerA:     question.AnswerA,
+				AnswerB:     question.AnswerB,
+				AnswerC:     question.AnswerC,
+				AnswerD:     question.AnswerD,
+				OptionOrder: question.OptionOrder,
+			}
+			if *answers {
+				q.Correct = question.DisplayedLetter(question.Correct)
+			}
+			sample = append(sample, q)
+		}
+		return writeJSON(os.Stdout, map[string]any{
+			"subject_id": subject.ID,
+			"seed":       *seed,
+			"questions":  sample,
+		})
+	}
+	fmt.Printf("%s, %d questions, %d minutes, seed %d\n", subject.Name, len(questions), subject.TestTime, *seed)
+	for i, question := range questions {
+		fmt.Printf("\n%d. %s\n", i+1, question.Content)
+		options := []string{question.AnswerA, question.AnswerB, question.AnswerC, question.AnswerD}
+		for j, option := range options {
+			if option != "" {
+				fmt.Printf("   %s. %s\n", model.OptionLetters[j], option)
+			}
+		}
+		if *answers {
+			fmt.Printf("   Answer: %s\n", question.DisplayedLetter(question.Correct))
+		}
+	}
+	return exitOK
+}
+
+func writeJSON(w io.Writer, v any) int {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(v); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitUsage
+	}
+	return exitOK
+}
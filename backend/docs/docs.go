@@ -24,44 +24,49 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/api/v1/officers": {
+        "/api/v1/admin/accommodations": {
             "get": {
-                "description": "Retrieves all officers in the system with their unit information",
-                "consumes": [
-                    "application/json"
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "Lists the extra time granted to officers, by officer then subject. A subject ID of 0 applies to every subject.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Officers"
+                    "Admin"
                 ],
-                "summary": "Get all officers",
+                "summary": "List time accommodations",
                 "responses": {
                     "200": {
-                        "description": "List of officers with unit information",
+                        "description": "Accommodations",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/controller.ListOfficerResponse"
-                            }
+                            "$ref": "#/definitions/controller.AccommodationListResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal server error",
+                    "401": {
+                        "description": "Missing or invalid token",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/controller.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Not an admin token",
+                        "schema": {
+                            "$ref": "#/definitions/controller.ErrorResponse"
                         }
                     }
                 }
-            }
-        },
-        "/api/v1/officers/{id}": {
-            "get": {
-                "description": "Retrieves a specific officer by their ID with unit information",
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Creates or replaces the accommodation of an officer in a subject, or in every subject when subject_id is 0 or omitted. Tests generated from now on get extra_minutes plus extra_percent of the subject's test time on top of it; tests already generated keep their duration. The accommodations file is rewritten and the change recorded in the audit trail.",
                 "consumes": [
                     "application/json"
                 ],
@@ -69,98 +74,73 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Officers"
+                    "Admin"
                 ],
-                "summary": "Get officer by ID",
+                "summary": "Grant extra time to an officer",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Officer ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Extra time of the officer",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controller.AccommodationRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Officer information with unit details",
+                        "description": "Saved accommodation",
                         "schema": {
-                            "$ref": "#/definitions/controller.OfficerResponse"
+                            "$ref": "#/definitions/controller.AccommodationResponse"
                         }
                     },
                     "400": {
-                        "description": "Bad request - invalid officer ID",
+                        "description": "Bad request - missing officer or negative extra time",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/controller.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Officer not found",
+                    "401": {
+                        "description": "Missing or invalid token",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/controller.ErrorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal server error",
+                    "403": {
+                        "description": "Not an admin token",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/controller.ErrorResponse"
                         }
-                    }
-                }
-            }
-        },
-        "/api/v1/subjects": {
-            "get": {
-                "description": "Get list of all subjects with basic information only (no questions or chapters)",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "subjects"
-                ],
-                "summary": "Get all subjects",
-                "responses": {
-                    "200": {
-                        "description": "success response with subjects list",
+                    },
+                    "404": {
+                        "description": "Officer or subject not found",
                         "schema": {
-                            "$ref": "#/definitions/controller.ListSubjectResponse"
+                            "$ref": "#/definitions/controller.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "internal server error",
+                        "description": "Internal server error",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/controller.ErrorResponse"
                         }
                     }
                 }
-            }
-        },
-        "/api/v1/tests/officer-subject": {
-            "get": {
-                "description": "Creates and returns a test for an officer in a specific subject with random questions",
-                "consumes": [
-                    "application/json"
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "Removes the accommodation of an officer in a subject, or the one for every subject when subjectID is 0 or omitted. Tests already generated keep their duration.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Tests"
+                    "Admin"
                 ],
-                "summary": "Get a test for an officer in a specific subject",
+                "summary": "Remove the extra time of an officer",
                 "parameters": [
                     {
                         "type": "integer",
@@ -171,127 +151,117 @@ const docTemplate = `{
                     },
                     {
                         "type": "integer",
-                        "description": "Subject ID",
+                        "description": "Subject ID, 0 for the accommodation in every subject",
                         "name": "subjectID",
-                        "in": "query",
-                        "required": true
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Test created successfully or existing test returned",
+                        "description": "Accommodation removed",
                         "schema": {
-                            "$ref": "#/definitions/controller.TestResponse"
+                            "$ref": "#/definitions/controller.AccommodationResponse"
                         }
                     },
                     "400": {
                         "description": "Bad request - missing or invalid parameters",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/controller.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/controller.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Not an admin token",
+                        "schema": {
+                            "$ref": "#/definitions/controller.ErrorResponse"
                         }
                     },
                     "404": {
-                        "description": "Officer or subject not found",
+                        "description": "Accommodation not found",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/controller.ErrorResponse"
                         }
                     },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/controller.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/api/v1/tests/start": {
-            "post": {
-                "description": "Starts a test by setting the start time for the specified officer and test",
-                "consumes": [
-                    "application/json"
+        "/api/v1/admin/audit-trail": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "Lists the admin actions on tests and submissions (extend, pause, resume, reset, void, invalidate), oldest first",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Tests"
+                    "Admin"
                 ],
-                "summary": "Start a test for an officer",
+                "summary": "Get the audit trail",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Officer ID",
+                        "description": "Only the actions on this officer",
                         "name": "officerID",
-                        "in": "query",
-                        "required": true
+                        "in": "query"
                     },
                     {
-                        "type": "integer",
-                        "description": "Test ID",
+                        "type": "string",
+                        "description": "Only the actions on this test",
                         "name": "testID",
-                        "in": "query",
-                        "required": true
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Test started successfully",
+                        "description": "Audit trail",
                         "schema": {
-                            "$ref": "#/definitions/controller.TestResponse"
+                            "$ref": "#/definitions/controller.AdminActionListResponse"
                         }
                     },
                     "400": {
-                        "description": "Bad request - missing or invalid parameters",
+                        "description": "Bad request - invalid officer ID",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/controller.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Test not found",
+                    "401": {
+                        "description": "Missing or invalid token",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/controller.ErrorResponse"
                         }
                     },
-                    "409": {
-                        "description": "Test already started",
+                    "403": {
+                        "description": "Not an admin token",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/controller.ErrorResponse"
                         }
                     },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/controller.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/api/v1/tests/submit": {
+        "/api/v1/admin/login": {
             "post": {
-                "description": "Submit answers for a test and get the calculated score",
+                "description": "Checks an administrator's username and password against the admins in config.json and returns a session token with the admin role, to send as \"Authorization: Bearer \u003ctoken\u003e\" to the admin routes",
                 "consumes": [
                     "application/json"
                 ],
@@ -299,130 +269,1683 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Tests"
+                    "Admin"
                 ],
-                "summary": "Submit test answers",
+                "summary": "Admin login",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Officer ID",
-                        "name": "officerID",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Test ID",
-                        "name": "testID",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "description": "Question ID to answer mapping",
-                        "name": "submission",
+                        "description": "Admin credentials",
+                        "name": "credentials",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/controller.AdminLoginRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Test submitted successfully with score",
+                        "description": "Login successful",
                         "schema": {
-                            "$ref": "#/definitions/controller.SubmissionResponse"
+                            "$ref": "#/definitions/controller.AdminLoginResponse"
                         }
                     },
                     "400": {
                         "description": "Bad request - missing or invalid parameters",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/controller.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Test not found",
+                    "401": {
+                        "description": "Invalid credentials",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/controller.ErrorResponse"
                         }
                     },
-                    "409": {
-                        "description": "Test already submitted or not started",
+                    "429": {
+                        "description": "Too many failed logins for the account or from the client address",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/controller.ErrorResponse"
                         }
                     },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/controller.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/api/v1/units": {
+        "/api/v1/admin/officers/{id}/submissions": {
             "get": {
-                "description": "Retrieves all units in the system",
-                "consumes": [
-                    "application/json"
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "Returns every submission of an officer, oldest first, including invalidated and voided ones",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Units"
+                    "Admin"
+                ],
+                "summary": "Get an officer's submissions",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Officer ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Get all units",
                 "responses": {
                     "200": {
-                        "description": "List of units",
+                        "description": "Submissions of the officer",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/controller.ListUnitResponse"
-                            }
+                            "$ref": "#/definitions/controller.SubmissionListResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal server error",
+                    "400": {
+                        "description": "Bad request - invalid officer ID",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/controller.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/controller.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Not an admin token",
+                        "schema": {
+                            "$ref": "#/definitions/controller.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Officer not found",
+                        "schema": {
+                            "$ref": "#/definitions/controller.ErrorResponse"
                         }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "controller.ListOfficerResponse": {
-            "type": "object",
-            "properties": {
-                "count": {
-                    "type": "integer"
-                },
-                "data": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/model.Officer"
+        },
+        "/api/v1/admin/question-bank/validation": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Checks the contest folder as it is on disk now and lists every problem with its file, sheet and row: folder names, unreadable files, questions without text, missing or invalid correct letters, empty options, duplicate questions and chapters with fewer questions than drawn per test. Errors would stop the server from starting, warnings would not. The loaded bank is not changed until the next restart.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Validate the question bank",
+                "responses": {
+                    "200": {
+                        "description": "Validation report",
+                        "schema": {
+                            "$ref": "#/definitions/controller.ValidationReportResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/controller.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Not an admin token",
+                        "schema": {
+                            "$ref": "#/definitions/controller.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/admin/submissions/{id}/void": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Cancels the score of a submission, which no longer counts toward the officer's score. The test stays submitted and still counts as an attempt. Recorded in the audit trail.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Void a submission",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Submission ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Why the submission is voided",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controller.VoidSubmissionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Voided submission",
+                        "schema": {
+                            "$ref": "#/definitions/controller.SubmissionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - missing reason",
+                        "schema": {
+                            "$ref": "#/definitions/controller.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/controller.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Not an admin token",
+                        "schema": {
+                            "$ref": "#/definitions/controller.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Submission not found",
+                        "schema": {
+                            "$ref": "#/definitions/controller.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Submission already voided",
+                        "schema": {
+                            "$ref": "#/definitions/controller.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/controller.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/admin/tests/in-progress": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lists every started test that is not submitted yet with its deadline, remaining time and number of saved answers",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "List tests in progress",
+                "responses": {
+                    "200": {
+                        "description": "Tests in progress",
+                        "schema": {
+                            "$ref": "#/definitions/controller.TestProgressListResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/controller.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Not an admin token",
+                        "schema": {
+                            "$ref": "#/definitions/controller.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/admin/tests/officer-subject": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns the latest test generated for an officer in a subject, including the correct answers",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Get an officer's test with the answer key",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Officer ID",
+                        "name": "officerID",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Subject ID",
+                        "name": "subjectID",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Test with answer key",
+                        "schema": {
+                            "$ref": "#/definitions/controller.AdminTestResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - missing or invalid parameters",
+                        "schema": {
+                            "$ref": "#/definitions/controller.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/controller.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Not an admin token",
+                        "schema": {
+                            "$ref": "#/definitions/controller.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Officer or test not found",
+                        "schema": {
+                            "$ref": "#/definitions/controller.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/admin/tests/{id}/audit": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Regenerates the paper of a test from the seed recorded when it was generated and compares it with the stored questions. Used to answer appeals.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Regenerate a test from its recorded seed",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Test ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Stored and regenerated paper",
+                        "schema": {
+                            "$ref": "#/definitions/controller.TestAuditResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/controller.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Not an admin token",
+                        "schema": {
+                            "$ref": "#/definitions/controller.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Test not found",
+                        "schema": {
+                            "$ref": "#/definitions/controller.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Test has no recorded seed or the bank no longer has enough questions",
+                        "schema": {
+                            "$ref": "#/definitions/controller.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/controller.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/admin/tests/{id}/extend": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Adds minutes to the duration of a test that is not submitted yet, for instance to give back the time lost to a crash. Recorded in the audit trail.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Extend a test",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Test ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Minutes to add and why",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controller.ExtendTestRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Extended test",
+                        "schema": {
+                            "$ref": "#/definitions/controller.AdminTestResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - minutes missing or not positive",
+                        "schema": {
+                            "$ref": "#/definitions/controller.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/controller.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Not an admin token",
+                        "schema": {
+                            "$ref": "#/definitions/controller.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Test not found",
+                        "schema": {
+                            "$ref": "#/definitions/controller.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Test already submitted or invalidated",
+                        "schema": {
+                            "$ref": "#/definitions/controller.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/controller.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/admin/tests/{id}/invalidate": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Closes a test for good: it can no longer be started or answered and its submission, if any, no longer counts toward the officer's score. The test still counts as an attempt.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Invalidate a test",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Test ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Why the test is invalidated",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controller.InvalidateTestRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Invalidated test",
+                        "schema": {
+                            "$ref": "#/definitions/controller.AdminTestResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - missing reason",
+                        "schema": {
+                            "$ref": "#/definitions/controller.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/controller.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Not an admin token",
+                        "schema": {
+                            "$ref": "#/definitions/controller.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Test not found",
+                        "schema": {
+                            "$ref": "#/definitions/controller.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Test already invalidated",
+                        "schema": {
+                            "$ref": "#/definitions/controller.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/controller.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/admin/tests/{id}/pause": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Stops the timer of a running test. While paused the officer can neither load the questions, answer nor submit, and the test is not auto-submitted. Recorded in the audit trail.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Pause a test",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Test ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Why the test is paused",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/controller.AdminActionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Paused test",
+                        "schema": {
+                            "$ref": "#/definitions/controller.AdminTestResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - invalid body",
+                        "schema": {
+                            "$ref": "#/definitions/controller.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/controller.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Not an admin token",
+                        "schema": {
+                            "$ref": "#/definitions/controller.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Test not found",
+                        "schema": {
+                            "$ref": "#/definitions/controller.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Test not started, already paused, expired, submitted or invalidated",
+                        "schema": {
+                            "$ref": "#/definitions/controller.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/controller.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/admin/tests/{id}/reset": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Discards a test that is not submitted yet, started or not, or that was auto-submitted, for instance after a crash. An auto-submission is voided. The test no longer counts as an attempt and the officer's next request for the subject generates a fresh paper. Recorded in the audit trail.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Reset a test",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Test ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Why the test is reset",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/controller.AdminActionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Discarded test",
+                        "schema": {
+                            "$ref": "#/definitions/controller.AdminTestResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - invalid body",
+                        "schema": {
+                            "$ref": "#/definitions/controller.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/controller.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Not an admin token",
+                        "schema": {
+                            "$ref": "#/definitions/controller.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Test not found",
+                        "schema": {
+                            "$ref": "#/definitions/controller.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Test submitted by the officer or invalidated",
+                        "schema": {
+                            "$ref": "#/definitions/controller.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/controller.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/admin/tests/{id}/resume": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Restarts the timer of a paused test, the deadline moves back by the time spent paused. Recorded in the audit trail.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Resume a paused test",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Test ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Why the test is resumed",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/controller.AdminActionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Resumed test",
+                        "schema": {
+                            "$ref": "#/definitions/controller.AdminTestResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - invalid body",
+                        "schema": {
+                            "$ref": "#/definitions/controller.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/controller.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Not an admin token",
+                        "schema": {
+                            "$ref": "#/definitions/controller.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Test not found",
+                        "schema": {
+                            "$ref": "#/definitions/controller.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Test not paused",
+                        "schema": {
+                            "$ref": "#/definitions/controller.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/controller.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/auth/login": {
+            "post": {
+                "description": "Checks an officer's ID and PIN and returns a signed session token to send as \"Authorization: Bearer \u003ctoken\u003e\"",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Officer login",
+                "parameters": [
+                    {
+                        "description": "Officer credentials",
+                        "name": "credentials",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controller.LoginRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Login successful",
+                        "schema": {
+                            "$ref": "#/definitions/controller.LoginResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - missing or invalid parameters",
+                        "schema": {
+                            "$ref": "#/definitions/controller.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid credentials",
+                        "schema": {
+                            "$ref": "#/definitions/controller.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many failed logins for the officer or from the client address",
+                        "schema": {
+                            "$ref": "#/definitions/controller.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/controller.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/officers": {
+            "get": {
+                "description": "Retrieves all officers in the system with their unit and the score kept in each subject. Submissions are only served by the admin API.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Officers"
+                ],
+                "summary": "Get all officers",
+                "responses": {
+                    "200": {
+                        "description": "List of officers with unit information",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/controller.ListOfficerResponse"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/controller.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/officers/{id}": {
+            "get": {
+                "description": "Retrieves a specific officer by their ID with their unit and the score kept in each subject",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Officers"
+                ],
+                "summary": "Get officer by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Officer ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Officer information with unit details",
+                        "schema": {
+                            "$ref": "#/definitions/controller.OfficerResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - invalid officer ID",
+                        "schema": {
+                            "$ref": "#/definitions/controller.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Officer not found",
+                        "schema": {
+                            "$ref": "#/definitions/controller.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/controller.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/subjects": {
+            "get": {
+                "description": "Get list of all subjects with basic information only (no questions or chapters)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subjects"
+                ],
+                "summary": "Get all subjects",
+                "responses": {
+                    "200": {
+                        "description": "success response with subjects list",
+                        "schema": {
+                            "$ref": "#/definitions/controller.ListSubjectResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/controller.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/tests/answers": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Saves one or more answers of a started test so they survive a refresh or a crash. A blank answer clears the question. The saved answers are returned with the test and graded on submission or auto-submission.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tests"
+                ],
+                "summary": "Save answers without submitting",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Test ID",
+                        "name": "testID",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Client key of the request, a retry with the same key and answers is not applied again",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Question ID to answer mapping",
+                        "name": "answers",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Every answer saved so far",
+                        "schema": {
+                            "$ref": "#/definitions/controller.SavedAnswersResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - missing parameters or invalid answers",
+                        "schema": {
+                            "$ref": "#/definitions/controller.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/controller.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Test not found",
+                        "schema": {
+                            "$ref": "#/definitions/controller.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Test not started, already submitted, paused or expired, or idempotency key reused",
+                        "schema": {
+                            "$ref": "#/definitions/controller.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/controller.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/tests/heartbeat": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns the server time together with the deadline and the remaining seconds of a test of the logged-in officer, computed from its start time. Clients poll it to correct drift and manual clock changes.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Clock"
+                ],
+                "summary": "Get the time left in a test",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Test ID",
+                        "name": "testID",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Server time and remaining time",
+                        "schema": {
+                            "$ref": "#/definitions/controller.ClockResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - missing parameters",
+                        "schema": {
+                            "$ref": "#/definitions/controller.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/controller.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Test not found",
+                        "schema": {
+                            "$ref": "#/definitions/controller.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/tests/officer-subject": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Creates a test for the logged-in officer in a specific subject with random questions and returns its metadata (subject, duration, number of questions) together with any answers saved so far. The questions themselves are only returned by the start call and GET /api/v1/tests/questions.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tests"
+                ],
+                "summary": "Get a test for an officer in a specific subject",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Subject ID",
+                        "name": "subjectID",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Test created successfully or existing test returned",
+                        "schema": {
+                            "$ref": "#/definitions/controller.TestResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - missing or invalid parameters",
+                        "schema": {
+                            "$ref": "#/definitions/controller.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/controller.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Officer or subject not found",
+                        "schema": {
+                            "$ref": "#/definitions/controller.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Test expired, retake cooldown has not passed or the subject has not enough questions",
+                        "schema": {
+                            "$ref": "#/definitions/controller.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/controller.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/tests/questions": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns a started test of the logged-in officer with its questions, for instance to resume after a refresh. Only available until the deadline plus the grace period.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tests"
+                ],
+                "summary": "Get the questions of a started test",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Test ID",
+                        "name": "testID",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Test with its questions",
+                        "schema": {
+                            "$ref": "#/definitions/controller.TestResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - missing parameters",
+                        "schema": {
+                            "$ref": "#/definitions/controller.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/controller.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Test not found",
+                        "schema": {
+                            "$ref": "#/definitions/controller.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Test not started, already submitted, paused or expired",
+                        "schema": {
+                            "$ref": "#/definitions/controller.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/controller.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/tests/start": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Starts a test of the logged-in officer by setting its start time and returns it with its questions",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tests"
+                ],
+                "summary": "Start a test for an officer",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Test ID",
+                        "name": "testID",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Test started successfully",
+                        "schema": {
+                            "$ref": "#/definitions/controller.TestResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - missing or invalid parameters",
+                        "schema": {
+                            "$ref": "#/definitions/controller.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/controller.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Test not found",
+                        "schema": {
+                            "$ref": "#/definitions/controller.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Test already started",
+                        "schema": {
+                            "$ref": "#/definitions/controller.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/controller.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/tests/submit": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Submit answers for a test and get the calculated score. Every key must be a question of the test and every non-blank value one of its option letters, otherwise nothing is recorded and the response lists each rejected entry. Submitted answers override the ones saved with PUT /api/v1/tests/answers, and an empty body submits the saved answers as they are. Questions left without an answer are recorded as unanswered (\"\"). The server clock decides whether the answers count: after the deadline plus the grace period the submission is recorded as late and only the answers saved in time are graded.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tests"
+                ],
+                "summary": "Submit test answers",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Test ID",
+                        "name": "testID",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Client key of the request, a retry with the same key and answers returns the original submission",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Question ID to answer mapping",
+                        "name": "submission",
+                        "in": "body",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Test submitted successfully with score",
+                        "schema": {
+                            "$ref": "#/definitions/controller.SubmissionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - missing parameters or invalid answers",
+                        "schema": {
+                            "$ref": "#/definitions/controller.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/controller.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Test not found",
+                        "schema": {
+                            "$ref": "#/definitions/controller.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Test already submitted, not started or paused, or idempotency key reused",
+                        "schema": {
+                            "$ref": "#/definitions/controller.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/controller.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/time": {
+            "get": {
+                "description": "Returns the server clock so clients can measure their offset from it",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Clock"
+                ],
+                "summary": "Get the server time",
+                "responses": {
+                    "200": {
+                        "description": "Server time",
+                        "schema": {
+                            "$ref": "#/definitions/controller.ClockResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/units": {
+            "get": {
+                "description": "Retrieves all units in the system",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Units"
+                ],
+                "summary": "Get all units",
+                "responses": {
+                    "200": {
+                        "description": "List of units",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/controller.ListUnitResponse"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/controller.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "controller.AccommodationListResponse": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.Accommodation"
+                    }
+                },
+                "message": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "controller.AccommodationRequest": {
+            "type": "object",
+            "required": [
+                "officer_id"
+            ],
+            "properties": {
+                "extra_minutes": {
+                    "description": "minutes added to the subject's test time",
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "extra_percent": {
+                    "description": "percentage of the subject's test time added on top",
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "note": {
+                    "type": "string"
+                },
+                "officer_id": {
+                    "type": "integer"
+                },
+                "subject_id": {
+                    "description": "0 for every subject",
+                    "type": "integer",
+                    "minimum": 0
+                }
+            }
+        },
+        "controller.AccommodationResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/model.Accommodation"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "controller.AdminActionListResponse": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.AdminAction"
+                    }
+                },
+                "message": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "controller.AdminActionRequest": {
+            "type": "object",
+            "properties": {
+                "reason": {
+                    "type": "string"
+                }
+            }
+        },
+        "controller.AdminLoginData": {
+            "type": "object",
+            "properties": {
+                "expires_at": {
+                    "type": "integer"
+                },
+                "token": {
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "controller.AdminLoginRequest": {
+            "type": "object",
+            "required": [
+                "password",
+                "username"
+            ],
+            "properties": {
+                "password": {
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "controller.AdminLoginResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/controller.AdminLoginData"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "controller.AdminTestResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/model.Test"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "controller.ClockResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/model.TestClock"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "controller.ErrorBody": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "details": {},
+                "message": {
+                    "type": "string"
+                },
+                "message_vi": {
+                    "type": "string"
+                },
+                "request_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "controller.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "$ref": "#/definitions/controller.ErrorBody"
+                },
+                "status": {
+                    "description": "always \"error\"",
+                    "type": "string"
+                }
+            }
+        },
+        "controller.ExtendTestRequest": {
+            "type": "object",
+            "required": [
+                "minutes"
+            ],
+            "properties": {
+                "minutes": {
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "reason": {
+                    "type": "string"
+                }
+            }
+        },
+        "controller.InvalidateTestRequest": {
+            "type": "object",
+            "required": [
+                "reason"
+            ],
+            "properties": {
+                "reason": {
+                    "type": "string"
+                }
+            }
+        },
+        "controller.ListOfficerResponse": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.PublicOfficer"
                     }
                 },
                 "message": {
@@ -473,11 +1996,91 @@ const docTemplate = `{
                 }
             }
         },
+        "controller.LoginData": {
+            "type": "object",
+            "properties": {
+                "expires_at": {
+                    "type": "integer"
+                },
+                "officer": {
+                    "$ref": "#/definitions/model.PublicOfficer"
+                },
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "controller.LoginRequest": {
+            "type": "object",
+            "required": [
+                "officer_id",
+                "pin"
+            ],
+            "properties": {
+                "officer_id": {
+                    "type": "integer"
+                },
+                "pin": {
+                    "type": "string"
+                }
+            }
+        },
+        "controller.LoginResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/controller.LoginData"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
         "controller.OfficerResponse": {
             "type": "object",
             "properties": {
                 "data": {
-                    "$ref": "#/definitions/model.Officer"
+                    "$ref": "#/definitions/model.PublicOfficer"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "controller.SavedAnswersResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/model.SavedAnswer"
+                    }
+                },
+                "message": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "controller.SubmissionListResponse": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.Submission"
+                    }
                 },
                 "message": {
                     "type": "string"
@@ -501,23 +2104,234 @@ const docTemplate = `{
                 }
             }
         },
+        "controller.TestAuditResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/model.TestAudit"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "controller.TestProgressListResponse": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.TestProgress"
+                    }
+                },
+                "message": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
         "controller.TestResponse": {
             "type": "object",
             "properties": {
                 "data": {
-                    "$ref": "#/definitions/model.Test"
+                    "$ref": "#/definitions/model.CandidateTest"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "controller.ValidationReportResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/utils.ValidationReport"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "controller.VoidSubmissionRequest": {
+            "type": "object",
+            "required": [
+                "reason"
+            ],
+            "properties": {
+                "reason": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.Accommodation": {
+            "type": "object",
+            "properties": {
+                "extra_minutes": {
+                    "description": "minutes added to the subject's test time",
+                    "type": "integer"
+                },
+                "extra_percent": {
+                    "description": "percentage of the subject's test time added on top",
+                    "type": "integer"
+                },
+                "note": {
+                    "description": "for instance the reference of the approval",
+                    "type": "string"
+                },
+                "officer_id": {
+                    "type": "integer"
+                },
+                "subject_id": {
+                    "description": "0 for every subject",
+                    "type": "integer"
+                }
+            }
+        },
+        "model.AdminAction": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "description": "see AdminAction*",
+                    "type": "string"
+                },
+                "admin": {
+                    "description": "username of the admin",
+                    "type": "string"
+                },
+                "at": {
+                    "description": "timestamp of the action",
+                    "type": "integer"
+                },
+                "detail": {
+                    "description": "what changed, for instance the minutes added",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "officer_id": {
+                    "type": "integer"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "submission_id": {
+                    "type": "string"
+                },
+                "test_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.CandidateQuestion": {
+            "type": "object",
+            "properties": {
+                "answer_a": {
+                    "type": "string"
+                },
+                "answer_b": {
+                    "type": "string"
+                },
+                "answer_c": {
+                    "type": "string"
+                },
+                "answer_d": {
+                    "type": "string"
+                },
+                "content": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.CandidateTest": {
+            "type": "object",
+            "properties": {
+                "attempt": {
+                    "type": "integer"
+                },
+                "contest_id": {
+                    "type": "string"
+                },
+                "duration": {
+                    "description": "in seconds",
+                    "type": "integer"
+                },
+                "extra_time": {
+                    "description": "seconds of Duration granted by the officer's accommodation",
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "invalidated": {
+                    "description": "an admin invalidated the test",
+                    "type": "boolean"
+                },
+                "is_finished": {
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "num_questions": {
+                    "type": "integer"
+                },
+                "officer": {
+                    "$ref": "#/definitions/model.Officer"
+                },
+                "paused": {
+                    "description": "an admin paused the timer",
+                    "type": "boolean"
+                },
+                "questions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.CandidateQuestion"
+                    }
+                },
+                "remaining_time": {
+                    "description": "time left for the test in seconds",
+                    "type": "integer"
+                },
+                "saved_answers": {
+                    "description": "answers saved so far, to resume after a refresh",
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/model.SavedAnswer"
+                    }
                 },
-                "message": {
-                    "type": "string"
+                "start_time": {
+                    "description": "timestamp when the test started",
+                    "type": "integer"
                 },
-                "status": {
-                    "type": "string"
+                "subject": {
+                    "$ref": "#/definitions/model.Subject"
                 }
             }
         },
         "model.Chapter": {
             "type": "object",
             "properties": {
+                "description": {
+                    "type": "string"
+                },
                 "folder_path": {
                     "description": "path to the folder containing questions",
                     "type": "string"
@@ -532,6 +2346,10 @@ const docTemplate = `{
                     "description": "number of questions of chapter in the test",
                     "type": "integer"
                 },
+                "order": {
+                    "description": "position among the chapters, from chapter.json",
+                    "type": "integer"
+                },
                 "questions": {
                     "type": "array",
                     "items": {
@@ -577,6 +2395,37 @@ const docTemplate = `{
                 }
             }
         },
+        "model.PublicOfficer": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "position": {
+                    "type": "string"
+                },
+                "rank": {
+                    "type": "string"
+                },
+                "score": {
+                    "description": "total of the subject scores",
+                    "type": "number"
+                },
+                "subjects": {
+                    "description": "subjects with a counted submission",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.SubjectScore"
+                    }
+                },
+                "unit": {
+                    "type": "string"
+                }
+            }
+        },
         "model.Question": {
             "type": "object",
             "properties": {
@@ -601,6 +2450,26 @@ const docTemplate = `{
                 },
                 "id": {
                     "type": "integer"
+                },
+                "option_order": {
+                    "description": "OptionOrder is set on questions of a generated test whose options were\nshuffled: OptionOrder[i] is the original letter of the option displayed\nat position i. Correct always keeps the original letter.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "model.SavedAnswer": {
+            "type": "object",
+            "properties": {
+                "answer": {
+                    "description": "letter as displayed to the officer, \"\" when cleared",
+                    "type": "string"
+                },
+                "saved_at": {
+                    "description": "timestamp of the save",
+                    "type": "integer"
                 }
             }
         },
@@ -634,31 +2503,88 @@ const docTemplate = `{
                     "description": "number of questions of subject in the test",
                     "type": "integer"
                 },
+                "order": {
+                    "description": "position among the subjects, from subject.json",
+                    "type": "integer"
+                },
                 "test_time": {
                     "description": "time limit for the test in minutes",
                     "type": "integer"
                 }
             }
         },
+        "model.SubjectScore": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "description": "counted submissions",
+                    "type": "integer"
+                },
+                "score": {
+                    "type": "number"
+                },
+                "subject_id": {
+                    "type": "integer"
+                },
+                "subject_name": {
+                    "type": "string"
+                },
+                "submitted_at": {
+                    "description": "time of the last counted submission",
+                    "type": "integer"
+                }
+            }
+        },
         "model.Submission": {
             "type": "object",
             "properties": {
                 "answers": {
-                    "description": "question ID to answer mapping",
+                    "description": "question ID to answer mapping, letters as displayed to the officer, \"\" when unanswered",
                     "type": "object",
                     "additionalProperties": {
                         "type": "string"
                     }
                 },
-                "id": {
+                "attempt": {
+                    "description": "attempt number of the test",
+                    "type": "integer"
+                },
+                "canonical_answers": {
+                    "description": "CanonicalAnswers holds the same answers mapped back to the letters of the\nquestion bank, which is what the score is computed from. Every question of\nthe test has an entry.",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "deadline": {
+                    "description": "timestamp the test had to be submitted by",
                     "type": "integer"
                 },
+                "id": {
+                    "type": "string"
+                },
+                "idempotency_key": {
+                    "description": "key the client sent with the submit request",
+                    "type": "string"
+                },
+                "invalidated": {
+                    "description": "the test was invalidated, the score does not count",
+                    "type": "boolean"
+                },
                 "officer_id": {
                     "type": "integer"
                 },
+                "payload_hash": {
+                    "description": "fingerprint of the submitted answers, to recognize retries",
+                    "type": "string"
+                },
                 "score": {
                     "type": "number"
                 },
+                "status": {
+                    "description": "how the submission was accepted, see SubmissionStatus*",
+                    "type": "string"
+                },
                 "subject_id": {
                     "description": "ID of the subject for which the test was taken",
                     "type": "integer"
@@ -672,13 +2598,33 @@ const docTemplate = `{
                     "type": "integer"
                 },
                 "test_id": {
+                    "type": "string"
+                },
+                "void_reason": {
+                    "description": "why the submission was voided",
+                    "type": "string"
+                },
+                "voided_at": {
+                    "description": "timestamp an admin voided the submission, the score does not count",
                     "type": "integer"
+                },
+                "voided_by": {
+                    "description": "admin who voided the submission",
+                    "type": "string"
                 }
             }
         },
         "model.Test": {
             "type": "object",
             "properties": {
+                "attempt": {
+                    "description": "1 for the first test of the officer in the subject, 2 for the first retake...",
+                    "type": "integer"
+                },
+                "bank_version": {
+                    "description": "fingerprint of the subject's question bank at generation time",
+                    "type": "string"
+                },
                 "contest_id": {
                     "type": "string"
                 },
@@ -686,19 +2632,61 @@ const docTemplate = `{
                     "description": "in seconds",
                     "type": "integer"
                 },
+                "excluded_question_ids": {
+                    "description": "ExcludedQuestionIDs are the questions seen in earlier attempts, which the\ngenerator avoided where the bank allowed. Needed to regenerate the paper.",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "extra_time": {
+                    "description": "seconds of Duration granted by the officer's accommodation",
+                    "type": "integer"
+                },
+                "finished_at": {
+                    "description": "timestamp when the test was submitted",
+                    "type": "integer"
+                },
                 "id": {
+                    "type": "string"
+                },
+                "invalid_reason": {
+                    "description": "why the test was invalidated",
+                    "type": "string"
+                },
+                "invalidated_at": {
+                    "description": "timestamp an admin invalidated the test, 0 if valid",
                     "type": "integer"
                 },
+                "invalidated_by": {
+                    "description": "admin who invalidated the test",
+                    "type": "string"
+                },
                 "is_finished": {
                     "description": "whether the test is finished",
                     "type": "boolean"
                 },
+                "last_save_hash": {
+                    "type": "string"
+                },
+                "last_save_key": {
+                    "description": "LastSaveKey is the idempotency key of the last answer save and\nLastSaveHash the fingerprint of its answers, to recognize a retry. The\nclient sends one save at a time, so only the last one can be retried.\nBoth are cleared when the test finishes.",
+                    "type": "string"
+                },
                 "name": {
                     "type": "string"
                 },
                 "officer": {
                     "$ref": "#/definitions/model.Officer"
                 },
+                "paused_at": {
+                    "description": "timestamp an admin paused the timer, 0 while it runs",
+                    "type": "integer"
+                },
+                "paused_for": {
+                    "description": "seconds spent paused before the last resume, added to the deadline",
+                    "type": "integer"
+                },
                 "questions": {
                     "description": "list of questions in the test",
                     "type": "array",
@@ -707,9 +2695,25 @@ const docTemplate = `{
                     }
                 },
                 "remaining_time": {
-                    "description": "time left for the test in seconds",
+                    "description": "time left for the test in seconds when it was read",
+                    "type": "integer"
+                },
+                "reset_at": {
+                    "description": "ResetAt is set when an admin discarded the test. A reset test is kept on\ndisk for the record but is no longer an attempt of the officer.",
                     "type": "integer"
                 },
+                "saved_answers": {
+                    "description": "SavedAnswers are the answers saved while the test is in progress, by\nquestion ID. They are graded when the test is submitted or auto-submitted.",
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/model.SavedAnswer"
+                    }
+                },
+                "seed": {
+                    "description": "seed the questions and option order were drawn with",
+                    "type": "string",
+                    "example": "0"
+                },
                 "start_time": {
                     "description": "timestamp when the test started",
                     "type": "integer"
@@ -718,6 +2722,187 @@ const docTemplate = `{
                     "$ref": "#/definitions/model.Subject"
                 }
             }
+        },
+        "model.TestAudit": {
+            "type": "object",
+            "properties": {
+                "bank_changed": {
+                    "description": "the bank was edited since the test was generated",
+                    "type": "boolean"
+                },
+                "current_bank_version": {
+                    "description": "fingerprint of the bank loaded now",
+                    "type": "string"
+                },
+                "matches": {
+                    "description": "the regenerated paper is identical to the stored one",
+                    "type": "boolean"
+                },
+                "regenerated": {
+                    "description": "questions generated again from the recorded seed",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.Question"
+                    }
+                },
+                "test": {
+                    "$ref": "#/definitions/model.Test"
+                }
+            }
+        },
+        "model.TestClock": {
+            "type": "object",
+            "properties": {
+                "deadline": {
+                    "description": "timestamp the test has to be submitted by",
+                    "type": "integer"
+                },
+                "grace_period": {
+                    "description": "seconds after the deadline during which answers still count",
+                    "type": "integer"
+                },
+                "is_finished": {
+                    "description": "the test is already submitted",
+                    "type": "boolean"
+                },
+                "paused": {
+                    "description": "an admin paused the timer, the remaining time does not decrease",
+                    "type": "boolean"
+                },
+                "remaining_time": {
+                    "description": "seconds left until the deadline",
+                    "type": "integer"
+                },
+                "server_time": {
+                    "description": "Unix seconds",
+                    "type": "integer"
+                },
+                "server_time_ms": {
+                    "description": "Unix milliseconds",
+                    "type": "integer"
+                },
+                "start_time": {
+                    "description": "timestamp when the test started",
+                    "type": "integer"
+                },
+                "test_id": {
+                    "description": "set when asked for a test",
+                    "type": "string"
+                }
+            }
+        },
+        "model.TestProgress": {
+            "type": "object",
+            "properties": {
+                "attempt": {
+                    "type": "integer"
+                },
+                "deadline": {
+                    "type": "integer"
+                },
+                "num_answered": {
+                    "description": "questions with a saved answer",
+                    "type": "integer"
+                },
+                "num_questions": {
+                    "type": "integer"
+                },
+                "officer_id": {
+                    "type": "integer"
+                },
+                "officer_name": {
+                    "type": "string"
+                },
+                "paused_at": {
+                    "description": "timestamp the timer was paused, 0 while it runs",
+                    "type": "integer"
+                },
+                "remaining_time": {
+                    "description": "seconds left until the deadline",
+                    "type": "integer"
+                },
+                "start_time": {
+                    "type": "integer"
+                },
+                "subject_id": {
+                    "type": "integer"
+                },
+                "subject_name": {
+                    "type": "string"
+                },
+                "test_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "utils.Problem": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "stable identifier, for instance missing_correct",
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "path": {
+                    "description": "folder or file, relative to the contest folder, or the officer roster",
+                    "type": "string"
+                },
+                "row": {
+                    "description": "1-based, as shown by Excel",
+                    "type": "integer"
+                },
+                "severity": {
+                    "type": "string"
+                },
+                "sheet": {
+                    "type": "string"
+                }
+            }
+        },
+        "utils.ValidationReport": {
+            "type": "object",
+            "properties": {
+                "chapters": {
+                    "type": "integer"
+                },
+                "contest_path": {
+                    "type": "string"
+                },
+                "errors": {
+                    "type": "integer"
+                },
+                "officer_path": {
+                    "type": "string"
+                },
+                "officers": {
+                    "type": "integer"
+                },
+                "problems": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/utils.Problem"
+                    }
+                },
+                "questions": {
+                    "type": "integer"
+                },
+                "subjects": {
+                    "type": "integer"
+                },
+                "warnings": {
+                    "type": "integer"
+                }
+            }
+        }
+    },
+    "securityDefinitions": {
+        "BearerAuth": {
+            "description": "Session token from /api/v1/auth/login, sent as \"Bearer \u003ctoken\u003e\"",
+            "type": "apiKey",
+            "name": "Authorization",
+            "in": "header"
         }
     }
 }`
@@ -0,0 +1,122 @@
+package auth
+
+import (
+	"sync"
+	"time"
+)
+
+// LoginGuard slows down guessing of PINs and passwords. An account is locked
+// after too many failed logins within the lockout window, and so is a client
+// address failing on any accounts. The address limit is meant to be higher:
+// a whole exam room usually logs in from behind one address.
+type LoginGuard struct {
+	maxAccountFailures int
+	maxAddressFailures int
+	lockout            time.Duration
+
+	mu        sync.Mutex
+	accounts  map[string]*failures
+	addresses map[string]*failures
+}
+
+// failures counts the failed logins of an account or address since first
+type failures struct {
+	count       int
+	first       time.Time
+	lockedUntil time.Time
+}
+
+// pruneThreshold is the number of tracked keys above which expired ones are
+// dropped, so that failures from many addresses do not pile up
+const pruneThreshold = 10000
+
+func NewLoginGuard(maxAccountFailures, maxAddressFailures int, lockout time.Duration) *LoginGuard {
+	return &LoginGuard{
+		maxAccountFailures: maxAccountFailures,
+		maxAddressFailures: maxAddressFailures,
+		lockout:            lockout,
+		accounts:           make(map[string]*failures),
+		addresses:          make(map[string]*failures),
+	}
+}
+
+// Attempt counts a login of the account from the address as failed until
+// Succeed says otherwise, and returns 0 when it may go on to the credential
+// check. While the account or the address is locked it returns how long the
+// lock lasts and counts nothing. Checking and counting under one lock keeps
+// parallel logins from all getting past the limit.
+func (g *LoginGuard) Attempt(account, address string, now time.Time) time.Duration {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	accountFailures := g.tracked(g.accounts, account, now)
+	addressFailures := g.tracked(g.addresses, address, now)
+	wait := lockedFor(accountFailures, now)
+	if addressWait := lockedFor(addressFailures, now); addressWait > wait {
+		wait = addressWait
+	}
+	if wait > 0 {
+		return wait
+	}
+	g.count(accountFailures, g.maxAccountFailures, now)
+	g.count(addressFailures, g.maxAddressFailures, now)
+	return 0
+}
+
+// tracked returns the failures of the key, starting over once they expired
+func (g *LoginGuard) tracked(tracked map[string]*failures, key string, now time.Time) *failures {
+	if len(tracked) >= pruneThreshold {
+		for k, f := range tracked {
+			if g.expired(f, now) {
+				delete(tracked, k)
+			}
+		}
+	}
+	f := tracked[key]
+	if f == nil || g.expired(f, now) {
+		f = &failures{first: now}
+		tracked[key] = f
+	}
+	return f
+}
+
+func (g *LoginGuard) count(f *failures, limit int, now time.Time) {
+	f.count++
+	if f.count >= limit && f.lockedUntil.IsZero() {
+		f.lockedUntil = now.Add(g.lockout)
+	}
+}
+
+func lockedFor(f *failures, now time.Time) time.Duration {
+	if !now.Before(f.lockedUntil) {
+		return 0
+	}
+	return f.lockedUntil.Sub(now)
+}
+
+// expired reports whether the failures no longer count: the lock is over, or
+// without a lock the window since the first one has passed. The next failure
+// after a lock starts a new count.
+func (g *LoginGuard) expired(f *failures, now time.Time) bool {
+	if !f.lockedUntil.IsZero() {
+		return !now.Before(f.lockedUntil)
+	}
+	return !now.Before(f.first.Add(g.lockout))
+}
+
+// Succeed forgets the failed logins of the account and takes back the attempt
+// counted against the address, lifting a lock that attempt caused. The other
+// failures of the address are kept, so logging in to one's own account does
+// not allow guessing others.
+func (g *LoginGuard) Succeed(account, address string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	delete(g.accounts, account)
+	f := g.addresses[address]
+	if f == nil || f.count == 0 {
+		return
+	}
+	f.count--
+	if f.count < g.maxAddressFailures {
+		f.lockedUntil = time.Time{}
+	}
+}
@@ -0,0 +1,84 @@
+package auth
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestLoginGuardLocksAccountsAndAddresses(t *testing.T) {
+	guard := NewLoginGuard(3, 5, time.Minute)
+	now := time.Unix(1_700_000_000, 0)
+
+	for i := 0; i < 3; i++ {
+		if wait := guard.Attempt("officer:1", "10.0.0.1", now); wait != 0 {
+			t.Fatalf("expected attempt %d to go on, got a lock of %v", i+1, wait)
+		}
+	}
+	if wait := guard.Attempt("officer:1", "10.0.0.2", now); wait != time.Minute {
+		t.Errorf("expected the account locked for a minute from any address, got %v", wait)
+	}
+	if wait := guard.Attempt("officer:2", "10.0.0.2", now); wait != 0 {
+		t.Errorf("expected other accounts not locked, got %v", wait)
+	}
+	if wait := guard.Attempt("officer:1", "10.0.0.3", now.Add(time.Minute)); wait != 0 {
+		t.Errorf("expected the lock over after a minute, got %v", wait)
+	}
+
+	// Failures spread over accounts still lock the address, refused
+	// attempts are not counted
+	guard.Attempt("officer:2", "10.0.0.1", now)
+	guard.Attempt("officer:3", "10.0.0.1", now)
+	if wait := guard.Attempt("officer:4", "10.0.0.1", now); wait != time.Minute {
+		t.Errorf("expected the address locked after 5 failures, got %v", wait)
+	}
+
+	// A success clears the account and takes back its attempt on the address
+	later := now.Add(2 * time.Minute)
+	for i := 0; i < 4; i++ {
+		guard.Attempt("officer:5", "10.0.0.4", later)
+		guard.Succeed("officer:5", "10.0.0.4")
+	}
+	guard.Attempt("officer:5", "10.0.0.4", later)
+	guard.Attempt("officer:5", "10.0.0.4", later)
+	if wait := guard.Attempt("officer:6", "10.0.0.4", later); wait != 0 {
+		t.Errorf("expected successful logins not to count against the address, got %v", wait)
+	}
+
+	// Failures older than the window are forgotten
+	guard.Attempt("officer:7", "10.0.0.5", later)
+	guard.Attempt("officer:7", "10.0.0.5", later.Add(2*time.Minute))
+	guard.Attempt("officer:7", "10.0.0.5", later.Add(2*time.Minute))
+	if wait := guard.Attempt("officer:7", "10.0.0.6", later.Add(2*time.Minute)); wait != 0 {
+		t.Errorf("expected failures outside the window not to lock the account, got %v", wait)
+	}
+}
+
+func TestLoginGuardLetsThroughAtMostTheLimitAtOnce(t *testing.T) {
+	guard := NewLoginGuard(3, 100, time.Minute)
+	now := time.Unix(1_700_000_000, 0)
+
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		checked int
+	)
+	start := make(chan struct{})
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			if guard.Attempt("admin:proctor", "10.0.0.1", now) == 0 {
+				mu.Lock()
+				checked++
+				mu.Unlock()
+			}
+		}()
+	}
+	close(start)
+	wg.Wait()
+	if checked != 3 {
+		t.Errorf("expected 3 of 50 parallel logins to reach the credential check, got %d", checked)
+	}
+}
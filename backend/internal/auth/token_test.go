@@ -0,0 +1,38 @@
+package auth
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSignerRoundTrip(t *testing.T) {
+	signer := NewSigner([]byte("secret"), time.Hour)
+	token, _, err := signer.Issue(Claims{OfficerID: 12, Role: RoleOfficer})
+	if err != nil {
+		t.Fatalf("Failed to issue token: %v", err)
+	}
+	claims, err := signer.Verify(token)
+	if err != nil {
+		t.Fatalf("Failed to verify token: %v", err)
+	}
+	if claims.OfficerID != 12 || claims.Role != RoleOfficer {
+		t.Errorf("unexpected claims: %+v", claims)
+	}
+
+	// Another secret must not accept the token
+	if _, err := NewSigner([]byte("other"), time.Hour).Verify(token); err != ErrInvalidToken {
+		t.Errorf("expected ErrInvalidToken, got %v", err)
+	}
+
+	// Tampering with the payload breaks the signature
+	payload, signature, _ := strings.Cut(token, ".")
+	if _, err := signer.Verify(payload + "x." + signature); err != ErrInvalidToken {
+		t.Errorf("expected ErrInvalidToken for tampered token, got %v", err)
+	}
+
+	expired, _, _ := NewSigner([]byte("secret"), -time.Minute).Issue(Claims{OfficerID: 12})
+	if _, err := signer.Verify(expired); err != ErrExpiredToken {
+		t.Errorf("expected ErrExpiredToken, got %v", err)
+	}
+}
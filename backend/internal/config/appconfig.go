@@ -2,7 +2,9 @@ package config
 
 import (
 	"encoding/json"
+	"fmt"
 	"os"
+	"path/filepath"
 
 	"github.com/lehaisonagentai3/free-contest/backend/internal/model"
 )
@@ -11,6 +13,64 @@ type AppConfig struct {
 	ListOfficer []*model.Officer `json:"list_officer,omitempty"`
 	ContestPath string           `json:"contest_path,omitempty"` // Path to the contest data directory contain multi subjects
 	OfficerPath string           `json:"officer_path,omitempty"` // Path to the officer data directory
+	DataPath    string           `json:"data_path,omitempty"`    // Path to the file storing tests and submissions, defaults to contest.db next to the config file
+	Admins      []*AdminAccount  `json:"admins,omitempty"`       // Accounts allowed to log in to the admin API, admin routes are disabled when empty
+	TokenSecret string           `json:"token_secret,omitempty"` // Secret used to sign session tokens, a random one is generated at startup when empty
+	TokenTTL    int              `json:"token_ttl,omitempty"`    // Session token lifetime in minutes, defaults to 480
+	GracePeriod int              `json:"grace_period,omitempty"` // Seconds after the deadline during which submissions are still graded
+	LoginLimit  LoginLimit       `json:"login_limit,omitempty"`  // Lockout after repeated failed logins
+	// Addresses or CIDRs of reverse proxies whose X-Forwarded-For header gives
+	// the client address. When empty the peer address is the client's.
+	TrustedProxies []string `json:"trusted_proxies,omitempty"`
+	// Path to the JSON file of per-officer time accommodations, defaults to
+	// accommodations.json next to the config file
+	AccommodationPath string `json:"accommodation_path,omitempty"`
+
+	RetakePolicy          RetakePolicy            `json:"retake_policy,omitempty"`           // Default retake policy for every subject
+	SubjectRetakePolicies map[string]RetakePolicy `json:"subject_retake_policies,omitempty"` // Retake policy per subject name, overrides the default
+}
+
+// AdminAccount is an administrator login. PasswordHash is a bcrypt hash.
+type AdminAccount struct {
+	Username     string `json:"username"`
+	PasswordHash string `json:"password_hash"`
+}
+
+// LoginLimit locks officer and admin logins after repeated failures, so that
+// short PINs cannot be guessed
+type LoginLimit struct {
+	MaxFailures        int `json:"max_failures,omitempty"`         // failed logins of one account before it is locked, defaults to 5
+	MaxAddressFailures int `json:"max_address_failures,omitempty"` // failed logins from one client address before it is locked, defaults to 50
+	Lockout            int `json:"lockout,omitempty"`              // minutes a lock lasts and failures are counted over, defaults to 15
+}
+
+// Which attempt counts toward the officer's score
+const (
+	ScoringBest    = "best"
+	ScoringLast    = "last"
+	ScoringAverage = "average"
+)
+
+// RetakePolicy controls how many times an officer may take a subject's test
+type RetakePolicy struct {
+	MaxAttempts int    `json:"max_attempts,omitempty"` // total attempts allowed, defaults to 1
+	Cooldown    int    `json:"cooldown,omitempty"`     // minutes to wait after finishing an attempt before the next one
+	Scoring     string `json:"scoring,omitempty"`      // best, last or average, defaults to best
+}
+
+// RetakePolicyFor returns the policy of a subject with defaults filled in
+func (c *AppConfig) RetakePolicyFor(subjectName string) RetakePolicy {
+	policy := c.RetakePolicy
+	if subjectPolicy, ok := c.SubjectRetakePolicies[subjectName]; ok {
+		policy = subjectPolicy
+	}
+	if policy.MaxAttempts <= 0 {
+		policy.MaxAttempts = 1
+	}
+	if policy.Scoring == "" {
+		policy.Scoring = ScoringBest
+	}
+	return policy
 }
 
 func LoadAppConfig(configFileJson string) (*AppConfig, error) {
@@ -23,10 +83,45 @@ func LoadAppConfig(configFileJson string) (*AppConfig, error) {
 	if err := json.Unmarshal(data, &config); err != nil {
 		return nil, err
 	}
+	for name, policy := range config.SubjectRetakePolicies {
+		if err := policy.validate(); err != nil {
+			return nil, fmt.Errorf("retake policy of subject %s: %w", name, err)
+		}
+	}
+	if err := config.RetakePolicy.validate(); err != nil {
+		return nil, fmt.Errorf("retake policy: %w", err)
+	}
+	if config.TokenTTL <= 0 {
+		config.TokenTTL = 480
+	}
+	if config.LoginLimit.MaxFailures <= 0 {
+		config.LoginLimit.MaxFailures = 5
+	}
+	if config.LoginLimit.MaxAddressFailures <= 0 {
+		config.LoginLimit.MaxAddressFailures = 50
+	}
+	if config.LoginLimit.Lockout <= 0 {
+		config.LoginLimit.Lockout = 15
+	}
+	if config.DataPath == "" {
+		config.DataPath = filepath.Join(filepath.Dir(configFileJson), "contest.db")
+	}
+	if config.AccommodationPath == "" {
+		config.AccommodationPath = filepath.Join(filepath.Dir(configFileJson), "accommodations.json")
+	}
 
 	return &config, nil
 }
 
+func (p RetakePolicy) validate() error {
+	switch p.Scoring {
+	case "", ScoringBest, ScoringLast, ScoringAverage:
+		return nil
+	default:
+		return fmt.Errorf("unknown scoring %q, expected best, last or average", p.Scoring)
+	}
+}
+
 func SaveAppConfig(configFileJson string, config *AppConfig) error {
 	data, err := json.Marshal(config)
 	if err != nil {
@@ -0,0 +1,703 @@
+package controller
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lehaisonagentai3/free-contest/backend/internal/auth"
+	"github.com/lehaisonagentai3/free-contest/backend/internal/model"
+	"github.com/lehaisonagentai3/free-contest/backend/internal/service"
+	"github.com/lehaisonagentai3/free-contest/backend/internal/utils"
+)
+
+type AdminController struct {
+	contestService *service.ContestService
+	signer         *auth.Signer
+	guard          *auth.LoginGuard
+}
+
+func NewAdminController(contestService *service.ContestService, signer *auth.Signer, guard *auth.LoginGuard) *AdminController {
+	return &AdminController{
+		contestService: contestService,
+		signer:         signer,
+		guard:          guard,
+	}
+}
+
+var errInvalidAdminCredentials = &APIError{http.StatusUnauthorized, "invalid_credentials", "Invalid username or password", "Tên đăng nhập hoặc mật khẩu không đúng", nil}
+
+type AdminLoginRequest struct {
+	Username string `json:"username" binding:"required"`
+	Password string `json:"password" binding:"required"`
+}
+
+type AdminLoginData struct {
+	Token     string `json:"token"`
+	ExpiresAt int64  `json:"expires_at"`
+	Username  string `json:"username"`
+}
+
+type AdminLoginResponse struct {
+	Data    *AdminLoginData `json:"data,omitempty"`
+	Message string          `json:"message,omitempty"`
+	Status  string          `json:"status,omitempty"`
+}
+
+// Login godoc
+// @Summary Admin login
+// @Description Checks an administrator's username and password against the admins in config.json and returns a session token with the admin role, to send as "Authorization: Bearer <token>" to the admin routes
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param credentials body AdminLoginRequest true "Admin credentials"
+// @Success 200 {object} AdminLoginResponse "Login successful"
+// @Failure 400 {object} ErrorResponse "Bad request - missing or invalid parameters"
+// @Failure 401 {object} ErrorResponse "Invalid credentials"
+// @Failure 429 {object} ErrorResponse "Too many failed logins for the account or from the client address"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /api/v1/admin/login [post]
+func (ac *AdminController) Login(c *gin.Context) {
+	var req AdminLoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		abortWithError(c, badRequest("Invalid request body: username and password are required", "Cần nhập tên đăng nhập và mật khẩu"))
+		return
+	}
+
+	account := adminAccount(req.Username)
+	if !attemptLogin(c, ac.guard, account) {
+		return
+	}
+	username, err := ac.contestService.AuthenticateAdmin(req.Username, req.Password)
+	if errors.Is(err, service.ErrInvalidCredentials) {
+		abortWithError(c, errInvalidAdminCredentials)
+		return
+	}
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+	ac.guard.Succeed(account, c.ClientIP())
+
+	token, claims, err := ac.signer.Issue(auth.Claims{Username: username, Role: auth.RoleAdmin})
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, AdminLoginResponse{
+		Data: &AdminLoginData{
+			Token:     token,
+			ExpiresAt: claims.ExpiresAt,
+			Username:  username,
+		},
+		Message: "Login successful",
+		Status:  "success",
+	})
+}
+
+// AdminTestResponse carries the full test including the answer key
+type AdminTestResponse struct {
+	Data    *model.Test `json:"data,omitempty"`
+	Message string      `json:"message,omitempty"`
+	Status  string      `json:"status,omitempty"`
+}
+
+// GetOfficerSubjectTest godoc
+// @Summary Get an officer's test with the answer key
+// @Description Returns the latest test generated for an officer in a subject, including the correct answers
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param officerID query int true "Officer ID"
+// @Param subjectID query int true "Subject ID"
+// @Success 200 {object} AdminTestResponse "Test with answer key"
+// @Failure 400 {object} ErrorResponse "Bad request - missing or invalid parameters"
+// @Failure 401 {object} ErrorResponse "Missing or invalid token"
+// @Failure 403 {object} ErrorResponse "Not an admin token"
+// @Failure 404 {object} ErrorResponse "Officer or test not found"
+// @Router /api/v1/admin/tests/officer-subject [get]
+func (ac *AdminController) GetOfficerSubjectTest(c *gin.Context) {
+	officerID, err := strconv.Atoi(c.Query("officerID"))
+	if err != nil {
+		abortWithError(c, badRequest("Invalid officerID: must be a valid integer", "officerID phải là số nguyên"))
+		return
+	}
+	subjectID, err := strconv.Atoi(c.Query("subjectID"))
+	if err != nil {
+		abortWithError(c, badRequest("Invalid subjectID: must be a valid integer", "subjectID phải là số nguyên"))
+		return
+	}
+
+	test, err := ac.contestService.GetTestForOfficer(officerID, subjectID)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, AdminTestResponse{
+		Data:    test,
+		Message: "Test retrieved successfully",
+		Status:  "success",
+	})
+}
+
+type TestAuditResponse struct {
+	Data    *model.TestAudit `json:"data,omitempty"`
+	Message string           `json:"message,omitempty"`
+	Status  string           `json:"status,omitempty"`
+}
+
+// AuditTest godoc
+// @Summary Regenerate a test from its recorded seed
+// @Description Regenerates the paper of a test from the seed recorded when it was generated and compares it with the stored questions. Used to answer appeals.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Test ID"
+// @Success 200 {object} TestAuditResponse "Stored and regenerated paper"
+// @Failure 401 {object} ErrorResponse "Missing or invalid token"
+// @Failure 403 {object} ErrorResponse "Not an admin token"
+// @Failure 404 {object} ErrorResponse "Test not found"
+// @Failure 409 {object} ErrorResponse "Test has no recorded seed or the bank no longer has enough questions"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /api/v1/admin/tests/{id}/audit [get]
+func (ac *AdminController) AuditTest(c *gin.Context) {
+	audit, err := ac.contestService.AuditTest(c.Param("id"))
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	message := "Regenerated paper matches the stored test"
+	if !audit.Matches {
+		message = "Regenerated paper differs from the stored test"
+	}
+	c.JSON(http.StatusOK, TestAuditResponse{
+		Data:    audit,
+		Message: message,
+		Status:  "success",
+	})
+}
+
+type TestProgressListResponse struct {
+	Data    []*model.TestProgress `json:"data"`
+	Count   int                   `json:"count"`
+	Message string                `json:"message,omitempty"`
+	Status  string                `json:"status,omitempty"`
+}
+
+// ListInProgressTests godoc
+// @Summary List tests in progress
+// @Description Lists every started test that is not submitted yet with its deadline, remaining time and number of saved answers
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} TestProgressListResponse "Tests in progress"
+// @Failure 401 {object} ErrorResponse "Missing or invalid token"
+// @Failure 403 {object} ErrorResponse "Not an admin token"
+// @Router /api/v1/admin/tests/in-progress [get]
+func (ac *AdminController) ListInProgressTests(c *gin.Context) {
+	tests := ac.contestService.ListInProgressTests()
+	c.JSON(http.StatusOK, TestProgressListResponse{
+		Data:    tests,
+		Count:   len(tests),
+		Message: "Tests in progress retrieved successfully",
+		Status:  "success",
+	})
+}
+
+type SubmissionListResponse struct {
+	Data    []*model.Submission `json:"data"`
+	Count   int                 `json:"count"`
+	Message string              `json:"message,omitempty"`
+	Status  string              `json:"status,omitempty"`
+}
+
+// GetOfficerSubmissions godoc
+// @Summary Get an officer's submissions
+// @Description Returns every submission of an officer, oldest first, including invalidated and voided ones
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Officer ID"
+// @Success 200 {object} SubmissionListResponse "Submissions of the officer"
+// @Failure 400 {object} ErrorResponse "Bad request - invalid officer ID"
+// @Failure 401 {object} ErrorResponse "Missing or invalid token"
+// @Failure 403 {object} ErrorResponse "Not an admin token"
+// @Failure 404 {object} ErrorResponse "Officer not found"
+// @Router /api/v1/admin/officers/{id}/submissions [get]
+func (ac *AdminController) GetOfficerSubmissions(c *gin.Context) {
+	officerID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		abortWithError(c, badRequest("Invalid officer ID format", "Mã cán bộ không hợp lệ"))
+		return
+	}
+
+	submissions, err := ac.contestService.GetOfficerSubmissions(officerID)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, SubmissionListResponse{
+		Data:    submissions,
+		Count:   len(submissions),
+		Message: "Submissions retrieved successfully",
+		Status:  "success",
+	})
+}
+
+type InvalidateTestRequest struct {
+	Reason string `json:"reason" binding:"required"`
+}
+
+// InvalidateTest godoc
+// @Summary Invalidate a test
+// @Description Closes a test for good: it can no longer be started or answered and its submission, if any, no longer counts toward the officer's score. The test still counts as an attempt.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Test ID"
+// @Param request body InvalidateTestRequest true "Why the test is invalidated"
+// @Success 200 {object} AdminTestResponse "Invalidated test"
+// @Failure 400 {object} ErrorResponse "Bad request - missing reason"
+// @Failure 401 {object} ErrorResponse "Missing or invalid token"
+// @Failure 403 {object} ErrorResponse "Not an admin token"
+// @Failure 404 {object} ErrorResponse "Test not found"
+// @Failure 409 {object} ErrorResponse "Test already invalidated"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /api/v1/admin/tests/{id}/invalidate [post]
+func (ac *AdminController) InvalidateTest(c *gin.Context) {
+	var req InvalidateTestRequest
+	if err := c.ShouldBindJSON(&req); err != nil || strings.TrimSpace(req.Reason) == "" {
+		abortWithError(c, badRequest("A reason is required", "Cần nhập lý do"))
+		return
+	}
+
+	test, err := ac.contestService.InvalidateTest(c.Param("id"), strings.TrimSpace(req.Reason), c.GetString(contextAdmin))
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, AdminTestResponse{
+		Data:    test,
+		Message: "Test invalidated",
+		Status:  "success",
+	})
+}
+
+type ExtendTestRequest struct {
+	Minutes int    `json:"minutes" binding:"required,min=1"`
+	Reason  string `json:"reason"`
+}
+
+// ExtendTest godoc
+// @Summary Extend a test
+// @Description Adds minutes to the duration of a test that is not submitted yet, for instance to give back the time lost to a crash. Recorded in the audit trail.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Test ID"
+// @Param request body ExtendTestRequest true "Minutes to add and why"
+// @Success 200 {object} AdminTestResponse "Extended test"
+// @Failure 400 {object} ErrorResponse "Bad request - minutes missing or not positive"
+// @Failure 401 {object} ErrorResponse "Missing or invalid token"
+// @Failure 403 {object} ErrorResponse "Not an admin token"
+// @Failure 404 {object} ErrorResponse "Test not found"
+// @Failure 409 {object} ErrorResponse "Test already submitted or invalidated"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /api/v1/admin/tests/{id}/extend [post]
+func (ac *AdminController) ExtendTest(c *gin.Context) {
+	var req ExtendTestRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		abortWithError(c, badRequest("minutes is required and must be a positive integer", "Số phút gia hạn phải là số nguyên dương"))
+		return
+	}
+
+	test, err := ac.contestService.ExtendTest(c.Param("id"), req.Minutes, strings.TrimSpace(req.Reason), c.GetString(contextAdmin))
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, AdminTestResponse{
+		Data:    test,
+		Message: "Test extended",
+		Status:  "success",
+	})
+}
+
+// AdminActionRequest carries the optional reason of an admin action
+type AdminActionRequest struct {
+	Reason string `json:"reason"`
+}
+
+// bindReason reads the optional reason of an admin action, an empty body has
+// none
+func bindReason(c *gin.Context) (string, bool) {
+	var req AdminActionRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		abortWithError(c, badRequest("Invalid request body", "Dữ liệu gửi lên không hợp lệ"))
+		return "", false
+	}
+	return strings.TrimSpace(req.Reason), true
+}
+
+// PauseTest godoc
+// @Summary Pause a test
+// @Description Stops the timer of a running test. While paused the officer can neither load the questions, answer nor submit, and the test is not auto-submitted. Recorded in the audit trail.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Test ID"
+// @Param request body AdminActionRequest false "Why the test is paused"
+// @Success 200 {object} AdminTestResponse "Paused test"
+// @Failure 400 {object} ErrorResponse "Bad request - invalid body"
+// @Failure 401 {object} ErrorResponse "Missing or invalid token"
+// @Failure 403 {object} ErrorResponse "Not an admin token"
+// @Failure 404 {object} ErrorResponse "Test not found"
+// @Failure 409 {object} ErrorResponse "Test not started, already paused, expired, submitted or invalidated"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /api/v1/admin/tests/{id}/pause [post]
+func (ac *AdminController) PauseTest(c *gin.Context) {
+	reason, ok := bindReason(c)
+	if !ok {
+		return
+	}
+
+	test, err := ac.contestService.PauseTest(c.Param("id"), reason, c.GetString(contextAdmin))
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, AdminTestResponse{
+		Data:    test,
+		Message: "Test paused",
+		Status:  "success",
+	})
+}
+
+// ResumeTest godoc
+// @Summary Resume a paused test
+// @Description Restarts the timer of a paused test, the deadline moves back by the time spent paused. Recorded in the audit trail.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Test ID"
+// @Param request body AdminActionRequest false "Why the test is resumed"
+// @Success 200 {object} AdminTestResponse "Resumed test"
+// @Failure 400 {object} ErrorResponse "Bad request - invalid body"
+// @Failure 401 {object} ErrorResponse "Missing or invalid token"
+// @Failure 403 {object} ErrorResponse "Not an admin token"
+// @Failure 404 {object} ErrorResponse "Test not found"
+// @Failure 409 {object} ErrorResponse "Test not paused"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /api/v1/admin/tests/{id}/resume [post]
+func (ac *AdminController) ResumeTest(c *gin.Context) {
+	reason, ok := bindReason(c)
+	if !ok {
+		return
+	}
+
+	test, err := ac.contestService.ResumeTest(c.Param("id"), reason, c.GetString(contextAdmin))
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, AdminTestResponse{
+		Data:    test,
+		Message: "Test resumed",
+		Status:  "success",
+	})
+}
+
+// ResetTest godoc
+// @Summary Reset a test
+// @Description Discards a test that is not submitted yet, started or not, or that was auto-submitted, for instance after a crash. An auto-submission is voided. The test no longer counts as an attempt and the officer's next request for the subject generates a fresh paper. Recorded in the audit trail.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Test ID"
+// @Param request body AdminActionRequest false "Why the test is reset"
+// @Success 200 {object} AdminTestResponse "Discarded test"
+// @Failure 400 {object} ErrorResponse "Bad request - invalid body"
+// @Failure 401 {object} ErrorResponse "Missing or invalid token"
+// @Failure 403 {object} ErrorResponse "Not an admin token"
+// @Failure 404 {object} ErrorResponse "Test not found"
+// @Failure 409 {object} ErrorResponse "Test submitted by the officer or invalidated"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /api/v1/admin/tests/{id}/reset [post]
+func (ac *AdminController) ResetTest(c *gin.Context) {
+	reason, ok := bindReason(c)
+	if !ok {
+		return
+	}
+
+	test, err := ac.contestService.ResetTest(c.Param("id"), reason, c.GetString(contextAdmin))
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, AdminTestResponse{
+		Data:    test,
+		Message: "Test reset",
+		Status:  "success",
+	})
+}
+
+type VoidSubmissionRequest struct {
+	Reason string `json:"reason" binding:"required"`
+}
+
+// VoidSubmission godoc
+// @Summary Void a submission
+// @Description Cancels the score of a submission, which no longer counts toward the officer's score. The test stays submitted and still counts as an attempt. Recorded in the audit trail.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Submission ID"
+// @Param request body VoidSubmissionRequest true "Why the submission is voided"
+// @Success 200 {object} SubmissionResponse "Voided submission"
+// @Failure 400 {object} ErrorResponse "Bad request - missing reason"
+// @Failure 401 {object} ErrorResponse "Missing or invalid token"
+// @Failure 403 {object} ErrorResponse "Not an admin token"
+// @Failure 404 {object} ErrorResponse "Submission not found"
+// @Failure 409 {object} ErrorResponse "Submission already voided"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /api/v1/admin/submissions/{id}/void [post]
+func (ac *AdminController) VoidSubmission(c *gin.Context) {
+	var req VoidSubmissionRequest
+	if err := c.ShouldBindJSON(&req); err != nil || strings.TrimSpace(req.Reason) == "" {
+		abortWithError(c, badRequest("A reason is required", "Cần nhập lý do"))
+		return
+	}
+
+	submission, err := ac.contestService.VoidSubmission(c.Param("id"), strings.TrimSpace(req.Reason), c.GetString(contextAdmin))
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, SubmissionResponse{
+		Data:    submission,
+		Message: "Submission voided",
+		Status:  "success",
+	})
+}
+
+type AdminActionListResponse struct {
+	Data    []*model.AdminAction `json:"data"`
+	Count   int                  `json:"count"`
+	Message string               `json:"message,omitempty"`
+	Status  string               `json:"status,omitempty"`
+}
+
+// ListAdminActions godoc
+// @Summary Get the audit trail
+// @Description Lists the admin actions on tests and submissions (extend, pause, resume, reset, void, invalidate), oldest first
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param officerID query int false "Only the actions on this officer"
+// @Param testID query string false "Only the actions on this test"
+// @Success 200 {object} AdminActionListResponse "Audit trail"
+// @Failure 400 {object} ErrorResponse "Bad request - invalid officer ID"
+// @Failure 401 {object} ErrorResponse "Missing or invalid token"
+// @Failure 403 {object} ErrorResponse "Not an admin token"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /api/v1/admin/audit-trail [get]
+func (ac *AdminController) ListAdminActions(c *gin.Context) {
+	officerID := 0
+	if value := c.Query("officerID"); value != "" {
+		var err error
+		if officerID, err = strconv.Atoi(value); err != nil {
+			abortWithError(c, badRequest("Invalid officerID: must be a valid integer", "officerID phải là số nguyên"))
+			return
+		}
+	}
+
+	actions, err := ac.contestService.ListAdminActions(officerID, c.Query("testID"))
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, AdminActionListResponse{
+		Data:    actions,
+		Count:   len(actions),
+		Message: "Audit trail retrieved successfully",
+		Status:  "success",
+	})
+}
+
+type AccommodationRequest struct {
+	OfficerID    int    `json:"officer_id" binding:"required"`
+	SubjectID    int    `json:"subject_id" binding:"min=0"`    // 0 for every subject
+	ExtraMinutes int    `json:"extra_minutes" binding:"min=0"` // minutes added to the subject's test time
+	ExtraPercent int    `json:"extra_percent" binding:"min=0"` // percentage of the subject's test time added on top
+	Note         string `json:"note"`
+}
+
+type AccommodationResponse struct {
+	Data    *model.Accommodation `json:"data,omitempty"`
+	Message string               `json:"message,omitempty"`
+	Status  string               `json:"status,omitempty"`
+}
+
+type AccommodationListResponse struct {
+	Data    []*model.Accommodation `json:"data"`
+	Count   int                    `json:"count"`
+	Message string                 `json:"message,omitempty"`
+	Status  string                 `json:"status,omitempty"`
+}
+
+// ListAccommodations godoc
+// @Summary List time accommodations
+// @Description Lists the extra time granted to officers, by officer then subject. A subject ID of 0 applies to every subject.
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} AccommodationListResponse "Accommodations"
+// @Failure 401 {object} ErrorResponse "Missing or invalid token"
+// @Failure 403 {object} ErrorResponse "Not an admin token"
+// @Router /api/v1/admin/accommodations [get]
+func (ac *AdminController) ListAccommodations(c *gin.Context) {
+	accommodations := ac.contestService.ListAccommodations()
+	c.JSON(http.StatusOK, AccommodationListResponse{
+		Data:    accommodations,
+		Count:   len(accommodations),
+		Message: "Accommodations retrieved successfully",
+		Status:  "success",
+	})
+}
+
+// SetAccommodation godoc
+// @Summary Grant extra time to an officer
+// @Description Creates or replaces the accommodation of an officer in a subject, or in every subject when subject_id is 0 or omitted. Tests generated from now on get extra_minutes plus extra_percent of the subject's test time on top of it; tests already generated keep their duration. The accommodations file is rewritten and the change recorded in the audit trail.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body AccommodationRequest true "Extra time of the officer"
+// @Success 200 {object} AccommodationResponse "Saved accommodation"
+// @Failure 400 {object} ErrorResponse "Bad request - missing officer or negative extra time"
+// @Failure 401 {object} ErrorResponse "Missing or invalid token"
+// @Failure 403 {object} ErrorResponse "Not an admin token"
+// @Failure 404 {object} ErrorResponse "Officer or subject not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /api/v1/admin/accommodations [put]
+func (ac *AdminController) SetAccommodation(c *gin.Context) {
+	var req AccommodationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		abortWithError(c, badRequest("officer_id is required and the extra time cannot be negative", "Cần nhập officer_id và thời gian cộng thêm không được âm"))
+		return
+	}
+	if req.ExtraMinutes == 0 && req.ExtraPercent == 0 {
+		abortWithError(c, badRequest("extra_minutes or extra_percent is required, delete the accommodation to remove it", "Cần nhập extra_minutes hoặc extra_percent"))
+		return
+	}
+
+	accommodation, err := ac.contestService.SetAccommodation(&model.Accommodation{
+		OfficerID:    req.OfficerID,
+		SubjectID:    req.SubjectID,
+		ExtraMinutes: req.ExtraMinutes,
+		ExtraPercent: req.ExtraPercent,
+		Note:         strings.TrimSpace(req.Note),
+	}, c.GetString(contextAdmin))
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, AccommodationResponse{
+		Data:    accommodation,
+		Message: "Accommodation saved",
+		Status:  "success",
+	})
+}
+
+// DeleteAccommodation godoc
+// @Summary Remove the extra time of an officer
+// @Description Removes the accommodation of an officer in a subject, or the one for every subject when subjectID is 0 or omitted. Tests already generated keep their duration.
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param officerID query int true "Officer ID"
+// @Param subjectID query int false "Subject ID, 0 for the accommodation in every subject"
+// @Success 200 {object} AccommodationResponse "Accommodation removed"
+// @Failure 400 {object} ErrorResponse "Bad request - missing or invalid parameters"
+// @Failure 401 {object} ErrorResponse "Missing or invalid token"
+// @Failure 403 {object} ErrorResponse "Not an admin token"
+// @Failure 404 {object} ErrorResponse "Accommodation not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /api/v1/admin/accommodations [delete]
+func (ac *AdminController) DeleteAccommodation(c *gin.Context) {
+	officerID, err := strconv.Atoi(c.Query("officerID"))
+	if err != nil {
+		abortWithError(c, badRequest("Invalid officerID: must be a valid integer", "officerID phải là số nguyên"))
+		return
+	}
+	subjectID := 0
+	if value := c.Query("subjectID"); value != "" {
+		if subjectID, err = strconv.Atoi(value); err != nil {
+			abortWithError(c, badRequest("Invalid subjectID: must be a valid integer", "subjectID phải là số nguyên"))
+			return
+		}
+	}
+
+	if err := ac.contestService.DeleteAccommodation(officerID, subjectID, c.GetString(contextAdmin)); err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, AccommodationResponse{
+		Message: "Accommodation removed",
+		Status:  "success",
+	})
+}
+
+type ValidationReportResponse struct {
+	Data    *utils.ValidationReport `json:"data,omitempty"`
+	Message string                  `json:"message,omitempty"`
+	Status  string                  `json:"status,omitempty"`
+}
+
+// ValidateQuestionBank godoc
+// @Summary Validate the question bank
+// @Description Checks the contest folder as it is on disk now and lists every problem with its file, sheet and row: folder names, unreadable files, questions without text, missing or invalid correct letters, empty options, duplicate questions and chapters with fewer questions than drawn per test. Errors would stop the server from starting, warnings would not. The loaded bank is not changed until the next restart.
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} ValidationReportResponse "Validation report"
+// @Failure 401 {object} ErrorResponse "Missing or invalid token"
+// @Failure 403 {object} ErrorResponse "Not an admin token"
+// @Router /api/v1/admin/question-bank/validation [get]
+func (ac *AdminController) ValidateQuestionBank(c *gin.Context) {
+	report := ac.contestService.ValidateQuestionBank()
+
+	message := "The question bank has no errors"
+	if report.HasErrors() {
+		message = "The question bank has errors"
+	}
+	c.JSON(http.StatusOK, ValidationReportResponse{
+		Data:    report,
+		Message: message,
+		Status:  "success",
+	})
+}
@@ -0,0 +1,123 @@
+package controller
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lehaisonagentai3/free-contest/backend/internal/auth"
+	"github.com/lehaisonagentai3/free-contest/backend/internal/model"
+	"github.com/lehaisonagentai3/free-contest/backend/internal/service"
+)
+
+type AuthController struct {
+	contestService *service.ContestService
+	signer         *auth.Signer
+	guard          *auth.LoginGuard
+}
+
+func NewAuthController(contestService *service.ContestService, signer *auth.Signer, guard *auth.LoginGuard) *AuthController {
+	return &AuthController{
+		contestService: contestService,
+		signer:         signer,
+		guard:          guard,
+	}
+}
+
+var errTooManyLogins = &APIError{http.StatusTooManyRequests, "too_many_login_attempts", "Too many failed logins, try again later", "Đăng nhập sai quá nhiều lần, vui lòng thử lại sau", nil}
+
+// attemptLogin counts a login of the account before its credentials are
+// checked, and answers with too_many_login_attempts and a Retry-After header
+// when the account or the client address is locked
+func attemptLogin(c *gin.Context, guard *auth.LoginGuard, account string) bool {
+	wait := guard.Attempt(account, c.ClientIP(), time.Now())
+	if wait <= 0 {
+		return true
+	}
+	c.Header("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+	abortWithError(c, errTooManyLogins)
+	return false
+}
+
+// officerAccount and adminAccount key the login failures of an account
+func officerAccount(officerID int) string {
+	return "officer:" + strconv.Itoa(officerID)
+}
+
+func adminAccount(username string) string {
+	return "admin:" + username
+}
+
+type LoginRequest struct {
+	OfficerID int    `json:"officer_id" binding:"required"`
+	PIN       string `json:"pin" binding:"required"`
+}
+
+type LoginData struct {
+	Token     string               `json:"token"`
+	ExpiresAt int64                `json:"expires_at"`
+	Officer   *model.PublicOfficer `json:"officer"`
+}
+
+type LoginResponse struct {
+	Data    *LoginData `json:"data,omitempty"`
+	Message string     `json:"message,omitempty"`
+	Status  string     `json:"status,omitempty"`
+}
+
+// Login godoc
+// @Summary Officer login
+// @Description Checks an officer's ID and PIN and returns a signed session token to send as "Authorization: Bearer <token>"
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param credentials body LoginRequest true "Officer credentials"
+// @Success 200 {object} LoginResponse "Login successful"
+// @Failure 400 {object} ErrorResponse "Bad request - missing or invalid parameters"
+// @Failure 401 {object} ErrorResponse "Invalid credentials"
+// @Failure 429 {object} ErrorResponse "Too many failed logins for the officer or from the client address"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /api/v1/auth/login [post]
+func (ac *AuthController) Login(c *gin.Context) {
+	var req LoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		abortWithError(c, badRequest("Invalid request body: officer_id and pin are required", "Cần nhập mã cán bộ và mã PIN"))
+		return
+	}
+
+	account := officerAccount(req.OfficerID)
+	if !attemptLogin(c, ac.guard, account) {
+		return
+	}
+	officer, err := ac.contestService.AuthenticateOfficer(req.OfficerID, req.PIN)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+	ac.guard.Succeed(account, c.ClientIP())
+
+	// A copy read under the officer's lock, with its scores
+	officer, err = ac.contestService.GetOfficerByID(officer.ID)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	token, claims, err := ac.signer.Issue(auth.Claims{OfficerID: officer.ID, Role: auth.RoleOfficer})
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, LoginResponse{
+		Data: &LoginData{
+			Token:     token,
+			ExpiresAt: claims.ExpiresAt,
+			Officer:   model.NewPublicOfficer(officer, ac.contestService.SubjectScores(officer)),
+		},
+		Message: "Login successful",
+		Status:  "success",
+	})
+}
@@ -0,0 +1,117 @@
+package controller
+
+import (
+	"net/http"
+	"sync"
+	"testing"
+)
+
+func TestOfficerLogin(t *testing.T) {
+	api := newTestAPI(t)
+
+	token := api.login(t, 1, "1234")
+	if rec := api.do(http.MethodGet, "/api/v1/tests/officer-subject?subjectID=1", token, nil); rec.Code != http.StatusOK {
+		t.Errorf("expected the token to open officer routes, got %d %s", rec.Code, rec.Body.String())
+	}
+
+	for _, credentials := range []map[string]any{
+		{"officer_id": 1, "pin": "4321"},
+		{"officer_id": 99, "pin": "1234"},
+	} {
+		rec := api.do(http.MethodPost, "/api/v1/auth/login", "", credentials)
+		if rec.Code != http.StatusUnauthorized {
+			t.Errorf("expected 401 for %v, got %d %s", credentials, rec.Code, rec.Body.String())
+		}
+		if jsonKeys(t, rec.Body.Bytes())["token"] {
+			t.Errorf("expected no token for %v", credentials)
+		}
+	}
+}
+
+func TestLoginLocksAfterRepeatedFailures(t *testing.T) {
+	api := newTestAPI(t)
+
+	// Officer 1 already has one failure from the test client
+	api.do(http.MethodPost, "/api/v1/auth/login", "", map[string]any{"officer_id": 1, "pin": "0000"})
+	api.login(t, 1, "1234")
+
+	for i := 0; i < 3; i++ {
+		api.do(http.MethodPost, "/api/v1/auth/login", "", map[string]any{"officer_id": 2, "pin": "0000"})
+	}
+	rec := api.do(http.MethodPost, "/api/v1/auth/login", "", map[string]any{"officer_id": 2, "pin": "5678"})
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected 429 with the right PIN once locked, got %d %s", rec.Code, rec.Body.String())
+	}
+	if rec.Header().Get("Retry-After") == "" {
+		t.Error("expected a Retry-After header")
+	}
+
+	// The address has 4 failures, 6 more on other accounts lock it for
+	// everyone, admins included
+	api.login(t, 1, "1234")
+	for i := 0; i < 6; i++ {
+		api.do(http.MethodPost, "/api/v1/admin/login", "", map[string]any{"username": "user" + string(rune('a'+i)), "password": "guess"})
+	}
+	if rec := api.do(http.MethodPost, "/api/v1/auth/login", "", map[string]any{"officer_id": 1, "pin": "1234"}); rec.Code != http.StatusTooManyRequests {
+		t.Errorf("expected the address locked, got %d %s", rec.Code, rec.Body.String())
+	}
+	if rec := api.do(http.MethodPost, "/api/v1/admin/login", "", map[string]any{"username": "proctor", "password": "secret"}); rec.Code != http.StatusTooManyRequests {
+		t.Errorf("expected the address locked for admins, got %d %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestParallelLoginsStopAtTheLimit(t *testing.T) {
+	api := newTestAPI(t)
+
+	// Every guess that reaches the password check answers 401, the others 429
+	codes := make(chan int, 20)
+	var wg sync.WaitGroup
+	start := make(chan struct{})
+	for i := 0; i < cap(codes); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			codes <- api.do(http.MethodPost, "/api/v1/admin/login", "", map[string]any{"username": "proctor", "password": "guess"}).Code
+		}()
+	}
+	close(start)
+	wg.Wait()
+	close(codes)
+
+	checked := 0
+	for code := range codes {
+		switch code {
+		case http.StatusUnauthorized:
+			checked++
+		case http.StatusTooManyRequests:
+		default:
+			t.Errorf("expected 401 or 429, got %d", code)
+		}
+	}
+	if checked != 3 {
+		t.Errorf("expected 3 of %d parallel logins to reach the password check, got %d", cap(codes), checked)
+	}
+}
+
+func TestAdminRoutesRefuseOfficerTokens(t *testing.T) {
+	api := newTestAPI(t)
+	officerToken := api.login(t, 1, "1234")
+	adminToken := api.login(t, 0, "secret")
+
+	for _, path := range []string{"/api/v1/admin/tests/in-progress", "/api/v1/admin/officers/1/submissions"} {
+		rec := api.do(http.MethodGet, path, officerToken, nil)
+		if rec.Code != http.StatusForbidden {
+			t.Errorf("%s: expected 403 for an officer token, got %d %s", path, rec.Code, rec.Body.String())
+		}
+		if rec := api.do(http.MethodGet, path, "", nil); rec.Code != http.StatusUnauthorized {
+			t.Errorf("%s: expected 401 without a token, got %d", path, rec.Code)
+		}
+		if rec := api.do(http.MethodGet, path, adminToken, nil); rec.Code != http.StatusOK {
+			t.Errorf("%s: expected 200 for the admin, got %d %s", path, rec.Code, rec.Body.String())
+		}
+	}
+	if rec := api.do(http.MethodGet, "/api/v1/tests/officer-subject?subjectID=1", adminToken, nil); rec.Code != http.StatusForbidden {
+		t.Errorf("expected officer routes to refuse the admin token, got %d", rec.Code)
+	}
+}
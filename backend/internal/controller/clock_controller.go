@@ -0,0 +1,80 @@
+package controller
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lehaisonagentai3/free-contest/backend/internal/model"
+	"github.com/lehaisonagentai3/free-contest/backend/internal/service"
+)
+
+type ClockController struct {
+	contestService *service.ContestService
+}
+
+func NewClockController(contestService *service.ContestService) *ClockController {
+	return &ClockController{
+		contestService: contestService,
+	}
+}
+
+type ClockResponse struct {
+	Data    *model.TestClock `json:"data,omitempty"`
+	Message string           `json:"message,omitempty"`
+	Status  string           `json:"status,omitempty"`
+}
+
+// GetServerTime godoc
+// @Summary Get the server time
+// @Description Returns the server clock so clients can measure their offset from it
+// @Tags Clock
+// @Produce json
+// @Success 200 {object} ClockResponse "Server time"
+// @Router /api/v1/time [get]
+func (cc *ClockController) GetServerTime(c *gin.Context) {
+	now := time.Now()
+	c.JSON(http.StatusOK, ClockResponse{
+		Data: &model.TestClock{
+			ServerTime:   now.Unix(),
+			ServerTimeMs: now.UnixMilli(),
+		},
+		Message: "Server time retrieved successfully",
+		Status:  "success",
+	})
+}
+
+// Heartbeat godoc
+// @Summary Get the time left in a test
+// @Description Returns the server time together with the deadline and the remaining seconds of a test of the logged-in officer, computed from its start time. Clients poll it to correct drift and manual clock changes.
+// @Tags Clock
+// @Produce json
+// @Security BearerAuth
+// @Param testID query string true "Test ID"
+// @Success 200 {object} ClockResponse "Server time and remaining time"
+// @Failure 400 {object} ErrorResponse "Bad request - missing parameters"
+// @Failure 401 {object} ErrorResponse "Missing or invalid token"
+// @Failure 404 {object} ErrorResponse "Test not found"
+// @Router /api/v1/tests/heartbeat [get]
+func (cc *ClockController) Heartbeat(c *gin.Context) {
+	// The officer comes from the session token
+	officerID := c.GetInt(contextOfficerID)
+
+	testID := c.Query("testID")
+	if testID == "" {
+		abortWithError(c, badRequest("testID query parameter is required", "Thiếu tham số testID"))
+		return
+	}
+
+	clock, err := cc.contestService.GetTestClock(officerID, testID)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, ClockResponse{
+		Data:    clock,
+		Message: "Remaining time retrieved successfully",
+		Status:  "success",
+	})
+}
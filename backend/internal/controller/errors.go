@@ -0,0 +1,159 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lehaisonagentai3/free-contest/backend/internal/service"
+	"github.com/lehaisonagentai3/free-contest/backend/internal/utils"
+)
+
+// contextRequestID is the gin context key holding the request ID
+const contextRequestID = "requestID"
+
+// APIError is an error with everything needed to answer the request
+type APIError struct {
+	HTTPStatus int
+	Code       string // machine-readable, stable across releases
+	Message    string // English
+	MessageVI  string // Vietnamese
+	Details    any
+}
+
+func (e *APIError) Error() string {
+	return e.Message
+}
+
+// ErrorBody is the error part of ErrorResponse
+type ErrorBody struct {
+	Code      string `json:"code"`
+	Message   string `json:"message"`
+	MessageVI string `json:"message_vi"`
+	RequestID string `json:"request_id,omitempty"`
+	Details   any    `json:"details,omitempty"`
+}
+
+// ErrorResponse is returned by every endpoint on failure
+type ErrorResponse struct {
+	Error  ErrorBody `json:"error"`
+	Status string    `json:"status"` // always "error"
+}
+
+// badRequest reports a missing or malformed request parameter
+func badRequest(message, messageVI string) *APIError {
+	return &APIError{http.StatusBadRequest, "invalid_parameter", message, messageVI, nil}
+}
+
+var (
+	errMissingToken  = &APIError{http.StatusUnauthorized, "missing_token", "Missing bearer token", "Thiếu mã phiên đăng nhập", nil}
+	errInvalidToken  = &APIError{http.StatusUnauthorized, "invalid_token", "Invalid or expired token", "Phiên đăng nhập không hợp lệ hoặc đã hết hạn", nil}
+	errForbidden     = &APIError{http.StatusForbidden, "forbidden", "Not allowed for this role", "Không có quyền truy cập", nil}
+	errRouteNotFound = &APIError{http.StatusNotFound, "route_not_found", "API endpoint not found", "Không tìm thấy API", nil}
+	errInternal      = &APIError{http.StatusInternalServerError, "internal_error", "Internal server error", "Lỗi hệ thống", nil}
+)
+
+// serviceErrors maps the service's errors to responses
+var serviceErrors = []struct {
+	err    error
+	apiErr APIError
+}{
+	{service.ErrOfficerNotFound, APIError{http.StatusNotFound, "officer_not_found", "Officer not found", "Không tìm thấy cán bộ", nil}},
+	{service.ErrSubjectNotFound, APIError{http.StatusNotFound, "subject_not_found", "Subject not found", "Không tìm thấy môn thi", nil}},
+	{service.ErrTestNotFound, APIError{http.StatusNotFound, "test_not_found", "Test not found", "Không tìm thấy bài thi", nil}},
+	{service.ErrTestExpired, APIError{http.StatusConflict, "test_expired", "Test is expired", "Bài thi đã hết thời gian", nil}},
+	{service.ErrTestAlreadyStarted, APIError{http.StatusConflict, "test_already_started", "Test already started", "Bài thi đã được bắt đầu", nil}},
+	{service.ErrTestNotStarted, APIError{http.StatusConflict, "test_not_started", "Test has not been started yet", "Bài thi chưa được bắt đầu", nil}},
+	{service.ErrTestAlreadySubmitted, APIError{http.StatusConflict, "test_already_submitted", "Test has already been submitted", "Bài thi đã được nộp", nil}},
+	{service.ErrRetakeNotAvailable, APIError{http.StatusConflict, "retake_not_available", "Retake is not available yet", "Chưa đến thời gian được thi lại", nil}},
+	{service.ErrInvalidCredentials, APIError{http.StatusUnauthorized, "invalid_credentials", "Invalid officer ID or PIN", "Mã cán bộ hoặc mã PIN không đúng", nil}},
+	{service.ErrTestInvalidated, APIError{http.StatusConflict, "test_invalidated", "Test has been invalidated", "Bài thi đã bị hủy", nil}},
+	{service.ErrTestPaused, APIError{http.StatusConflict, "test_paused", "Test is paused", "Bài thi đang tạm dừng", nil}},
+	{service.ErrTestNotPaused, APIError{http.StatusConflict, "test_not_paused", "Test is not paused", "Bài thi không ở trạng thái tạm dừng", nil}},
+	{service.ErrSubmissionNotFound, APIError{http.StatusNotFound, "submission_not_found", "Submission not found", "Không tìm thấy bài nộp", nil}},
+	{service.ErrAccommodationNotFound, APIError{http.StatusNotFound, "accommodation_not_found", "Accommodation not found", "Không tìm thấy chế độ cộng thời gian", nil}},
+	{service.ErrSubmissionVoided, APIError{http.StatusConflict, "submission_voided", "Submission has already been voided", "Bài nộp đã bị hủy kết quả", nil}},
+	{service.ErrNotEnoughQuestions, APIError{http.StatusConflict, "not_enough_questions", "Subject does not have enough questions for a test", "Môn thi không đủ câu hỏi để tạo đề", nil}},
+	{service.ErrNoSeed, APIError{http.StatusConflict, "test_has_no_seed", "Test has no recorded seed and cannot be audited", "Bài thi không lưu mã sinh đề nên không thể kiểm tra lại", nil}},
+	{service.ErrIdempotencyKeyReused, APIError{http.StatusConflict, "idempotency_key_reused", "Idempotency key already used for a different request", "Idempotency-Key đã được dùng cho một yêu cầu khác", nil}},
+}
+
+// toAPIError maps any error to a response. Unknown errors are internal.
+func toAPIError(err error) *APIError {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr
+	}
+	var invalid *service.AnswerValidationError
+	if errors.As(err, &invalid) {
+		return &APIError{http.StatusBadRequest, "invalid_answers", "Invalid answers", "Đáp án không hợp lệ", invalid.Errors}
+	}
+	for _, mapping := range serviceErrors {
+		if errors.Is(err, mapping.err) {
+			apiErr := mapping.apiErr
+			return &apiErr
+		}
+	}
+	return errInternal
+}
+
+// abortWithError records err for ErrorHandler and stops the handler chain
+func abortWithError(c *gin.Context, err error) {
+	_ = c.Error(err)
+	c.Abort()
+}
+
+// RequestID tags every request with an ID, taken from the X-Request-ID header
+// when the client sends one, and echoes it in the response.
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader("X-Request-ID")
+		if requestID == "" || len(requestID) > 64 {
+			requestID = utils.NewID()
+		}
+		c.Set(contextRequestID, requestID)
+		c.Header("X-Request-ID", requestID)
+		c.Next()
+	}
+}
+
+// ErrorHandler writes the ErrorResponse for the last error a handler recorded
+func ErrorHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+		if len(c.Errors) == 0 || c.Writer.Written() {
+			return
+		}
+		err := c.Errors.Last().Err
+		apiErr := toAPIError(err)
+		requestID := c.GetString(contextRequestID)
+		if apiErr.HTTPStatus >= http.StatusInternalServerError {
+			log.Printf("Request %s %s %s failed: %v", requestID, c.Request.Method, c.Request.URL.Path, err)
+		}
+		c.JSON(apiErr.HTTPStatus, ErrorResponse{
+			Error: ErrorBody{
+				Code:      apiErr.Code,
+				Message:   apiErr.Message,
+				MessageVI: apiErr.MessageVI,
+				RequestID: requestID,
+				Details:   apiErr.Details,
+			},
+			Status: "error",
+		})
+	}
+}
+
+// Recovery turns a panic in a handler into an internal_error response. It
+// goes after ErrorHandler, which writes the response.
+func Recovery() gin.HandlerFunc {
+	return gin.CustomRecovery(func(c *gin.Context, recovered any) {
+		abortWithError(c, fmt.Errorf("panic: %v", recovered))
+	})
+}
+
+// RouteNotFound answers requests to unknown API routes
+func RouteNotFound(c *gin.Context) {
+	abortWithError(c, errRouteNotFound)
+}
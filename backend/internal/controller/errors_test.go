@@ -0,0 +1,58 @@
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lehaisonagentai3/free-contest/backend/internal/service"
+)
+
+func TestErrorEnvelope(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(RequestID(), ErrorHandler(), Recovery())
+	router.GET("/wrapped", func(c *gin.Context) {
+		abortWithError(c, fmt.Errorf("loading test: %w", service.ErrTestNotFound))
+	})
+	router.GET("/unknown", func(c *gin.Context) {
+		abortWithError(c, fmt.Errorf("disk full"))
+	})
+	router.GET("/panic", func(c *gin.Context) {
+		var test *struct{ ID string }
+		c.String(http.StatusOK, test.ID)
+	})
+
+	cases := []struct {
+		path   string
+		status int
+		code   string
+	}{
+		{"/wrapped", http.StatusNotFound, "test_not_found"},
+		{"/unknown", http.StatusInternalServerError, "internal_error"},
+		{"/panic", http.StatusInternalServerError, "internal_error"},
+	}
+	for _, tc := range cases {
+		req := httptest.NewRequest(http.MethodGet, tc.path, nil)
+		req.Header.Set("X-Request-ID", "req-1")
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		if rec.Code != tc.status {
+			t.Errorf("%s: expected status %d, got %d", tc.path, tc.status, rec.Code)
+		}
+		var body ErrorResponse
+		if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+			t.Fatalf("%s: invalid body %s: %v", tc.path, rec.Body.String(), err)
+		}
+		if body.Status != "error" || body.Error.Code != tc.code || body.Error.RequestID != "req-1" {
+			t.Errorf("%s: unexpected envelope %+v", tc.path, body)
+		}
+		if body.Error.Message == "" || body.Error.MessageVI == "" {
+			t.Errorf("%s: expected English and Vietnamese messages, got %+v", tc.path, body.Error)
+		}
+	}
+}
@@ -0,0 +1,176 @@
+package controller
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lehaisonagentai3/free-contest/backend/internal/auth"
+	"github.com/lehaisonagentai3/free-contest/backend/internal/config"
+	"github.com/lehaisonagentai3/free-contest/backend/internal/service"
+	"github.com/xuri/excelize/v2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// testAPI serves the routes of cmd/api over a contest and an officer roster
+// written to a temporary folder. Officer 1 has PIN 1234, officer 2 PIN 5678
+// and the admin "proctor" the password "secret". Logins lock after 3
+// failures of an account or 10 from the test client's address.
+type testAPI struct {
+	router  *gin.Engine
+	service *service.ContestService
+}
+
+func newTestAPI(t *testing.T) *testAPI {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	root := t.TempDir()
+
+	questions := [][]any{{"Nội dung", "A", "B", "C", "D", "Đáp án"}}
+	for i := 1; i <= 6; i++ {
+		questions = append(questions, []any{fmt.Sprintf("Câu hỏi %d", i), "Một", "Hai", "Ba", "Bốn", string(rune('A' + i%4))})
+	}
+	writeSheet(t, filepath.Join(root, "contest", "Điều lệnh - 30 - phút", "Chương 1 - 4 - câu", "cau_hoi.xlsx"), questions)
+	writeSheet(t, filepath.Join(root, "officers.xlsx"), [][]any{
+		{"ID", "Họ tên", "Cấp bậc", "Chức vụ", "Đơn vị", "PIN"},
+		{1, "Nguyễn Văn A", "Đại úy", "Đại đội trưởng", "Đại đội 1", "1234"},
+		{2, "Trần Văn B", "Thượng úy", "Trung đội trưởng", "Đại đội 1", "5678"},
+	})
+	hash, err := bcrypt.GenerateFromPassword([]byte("secret"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	conf := &config.AppConfig{
+		ContestPath:       filepath.Join(root, "contest"),
+		OfficerPath:       filepath.Join(root, "officers.xlsx"),
+		DataPath:          filepath.Join(root, "contest.db"),
+		AccommodationPath: filepath.Join(root, "accommodations.json"),
+		Admins:            []*config.AdminAccount{{Username: "proctor", PasswordHash: string(hash)}},
+	}
+	contestService, err := service.NewContestService(conf)
+	if err != nil {
+		t.Fatalf("Failed to create service: %v", err)
+	}
+	t.Cleanup(func() { contestService.Close() })
+
+	signer := auth.NewSigner([]byte("test-secret"), time.Hour)
+	guard := auth.NewLoginGuard(3, 10, time.Minute)
+	testController := NewTestController(contestService)
+	officerController := NewOfficerController(contestService)
+	adminController := NewAdminController(contestService, signer, guard)
+	authController := NewAuthController(contestService, signer, guard)
+
+	router := gin.New()
+	router.Use(RequestID(), ErrorHandler(), Recovery())
+	v1 := router.Group("/api/v1")
+	v1.POST("/auth/login", authController.Login)
+	tests := v1.Group("/tests", RoleRequired(signer, auth.RoleOfficer))
+	tests.GET("/officer-subject", testController.GetSubjectTestForOfficer)
+	tests.POST("/start", testController.StartTest)
+	tests.GET("/questions", testController.GetTestQuestions)
+	tests.POST("/submit", testController.SubmitTest)
+	tests.PUT("/answers", testController.SaveAnswers)
+	v1.GET("/officers", officerController.GetAllOfficers)
+	v1.GET("/officers/:id", officerController.GetOfficerByID)
+	v1.POST("/admin/login", adminController.Login)
+	admin := v1.Group("/admin", RoleRequired(signer, auth.RoleAdmin))
+	admin.GET("/tests/officer-subject", adminController.GetOfficerSubjectTest)
+	admin.GET("/tests/in-progress", adminController.ListInProgressTests)
+	admin.GET("/officers/:id/submissions", adminController.GetOfficerSubmissions)
+
+	return &testAPI{router: router, service: contestService}
+}
+
+// writeSheet writes the rows to the first sheet of a new workbook
+func writeSheet(t *testing.T, path string, rows [][]any) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	f := excelize.NewFile()
+	defer f.Close()
+	for i, row := range rows {
+		cell, _ := excelize.CoordinatesToCellName(1, i+1)
+		f.SetSheetRow("Sheet1", cell, &row)
+	}
+	if err := f.SaveAs(path); err != nil {
+		t.Fatalf("Failed to write workbook: %v", err)
+	}
+}
+
+// do sends a request with the optional bearer token and JSON body
+func (api *testAPI) do(method, path, token string, body any) *httptest.ResponseRecorder {
+	var reader *bytes.Reader
+	if body != nil {
+		data, _ := json.Marshal(body)
+		reader = bytes.NewReader(data)
+	} else {
+		reader = bytes.NewReader(nil)
+	}
+	req := httptest.NewRequest(method, path, reader)
+	req.Header.Set("Content-Type", "application/json")
+	req.RemoteAddr = "192.0.2.1:1234"
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	api.router.ServeHTTP(rec, req)
+	return rec
+}
+
+// login returns the session token of an officer, or of the admin when
+// officerID is 0
+func (api *testAPI) login(t *testing.T, officerID int, pin string) string {
+	t.Helper()
+	var rec *httptest.ResponseRecorder
+	if officerID == 0 {
+		rec = api.do(http.MethodPost, "/api/v1/admin/login", "", map[string]any{"username": "proctor", "password": pin})
+	} else {
+		rec = api.do(http.MethodPost, "/api/v1/auth/login", "", map[string]any{"officer_id": officerID, "pin": pin})
+	}
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Failed to log in: %d %s", rec.Code, rec.Body.String())
+	}
+	var body struct {
+		Data struct {
+			Token string `json:"token"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil || body.Data.Token == "" {
+		t.Fatalf("No token in %s: %v", rec.Body.String(), err)
+	}
+	return body.Data.Token
+}
+
+// jsonKeys returns every object key found anywhere in a JSON document
+func jsonKeys(t *testing.T, data []byte) map[string]bool {
+	t.Helper()
+	var document any
+	if err := json.Unmarshal(data, &document); err != nil {
+		t.Fatalf("Invalid JSON %s: %v", data, err)
+	}
+	keys := make(map[string]bool)
+	var walk func(any)
+	walk = func(value any) {
+		switch value := value.(type) {
+		case map[string]any:
+			for key, child := range value {
+				keys[key] = true
+				walk(child)
+			}
+		case []any:
+			for _, child := range value {
+				walk(child)
+			}
+		}
+	}
+	walk(document)
+	return keys
+}
@@ -0,0 +1,56 @@
+package controller
+
+import (
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lehaisonagentai3/free-contest/backend/internal/auth"
+	"github.com/lehaisonagentai3/free-contest/backend/internal/service"
+)
+
+// Gin context keys holding the authenticated user
+const (
+	contextOfficerID = "officerID" // officer ID of an officer token
+	contextAdmin     = "admin"     // username of an admin token
+)
+
+// idempotencyKey reads the optional Idempotency-Key header. It aborts the
+// request and returns false when the key is too long.
+func idempotencyKey(c *gin.Context) (string, bool) {
+	key := strings.TrimSpace(c.GetHeader("Idempotency-Key"))
+	if len(key) > service.MaxIdempotencyKeyLength {
+		abortWithError(c, badRequest("Idempotency-Key is too long", "Idempotency-Key quá dài"))
+		return "", false
+	}
+	return key, true
+}
+
+// RoleRequired verifies the bearer token and only lets requests through
+// when it was issued for the role. It stores the officer ID, or the admin
+// username, the token was issued for in the context.
+func RoleRequired(signer *auth.Signer, role string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		header := c.GetHeader("Authorization")
+		token, ok := strings.CutPrefix(header, "Bearer ")
+		if !ok || token == "" {
+			abortWithError(c, errMissingToken)
+			return
+		}
+		claims, err := signer.Verify(token)
+		if err != nil {
+			abortWithError(c, errInvalidToken)
+			return
+		}
+		if claims.Role != role {
+			abortWithError(c, errForbidden)
+			return
+		}
+		switch role {
+		case auth.RoleOfficer:
+			c.Set(contextOfficerID, claims.OfficerID)
+		case auth.RoleAdmin:
+			c.Set(contextAdmin, claims.Username)
+		}
+		c.Next()
+	}
+}
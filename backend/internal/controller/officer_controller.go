@@ -6,7 +6,6 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/lehaisonagentai3/free-contest/backend/internal/model"
-	_ "github.com/lehaisonagentai3/free-contest/backend/internal/model"
 	"github.com/lehaisonagentai3/free-contest/backend/internal/service"
 )
 
@@ -21,31 +20,35 @@ func NewOfficerController(contestService *service.ContestService) *OfficerContro
 }
 
 type ListOfficerResponse struct {
-	Data    []*model.Officer `json:"data"`
-	Count   int              `json:"count"`
-	Message string           `json:"message"`
-	Status  string           `json:"status"`
+	Data    []*model.PublicOfficer `json:"data"`
+	Count   int                    `json:"count"`
+	Message string                 `json:"message"`
+	Status  string                 `json:"status"`
 }
 
 type OfficerResponse struct {
-	Data    *model.Officer `json:"data"`
-	Message string         `json:"message"`
-	Status  string         `json:"status"`
+	Data    *model.PublicOfficer `json:"data"`
+	Message string               `json:"message"`
+	Status  string               `json:"status"`
 }
 
 // GetAllOfficers godoc
 // @Summary Get all officers
-// @Description Retrieves all officers in the system with their unit information
+// @Description Retrieves all officers in the system with their unit and the score kept in each subject. Submissions are only served by the admin API.
 // @Tags Officers
 // @Accept json
 // @Produce json
 // @Success 200 {array} ListOfficerResponse "List of officers with unit information"
-// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 500 {object} ErrorResponse "Internal server error"
 // @Router /api/v1/officers [get]
 func (oc *OfficerController) GetAllOfficers(c *gin.Context) {
 	officers := oc.contestService.GetAllOfficers()
+	views := make([]*model.PublicOfficer, 0, len(officers))
+	for _, officer := range officers {
+		views = append(views, model.NewPublicOfficer(officer, oc.contestService.SubjectScores(officer)))
+	}
 	c.JSON(http.StatusOK, ListOfficerResponse{
-		Data:    officers,
+		Data:    views,
 		Count:   len(officers),
 		Message: "Officers retrieved successfully",
 		Status:  "success",
@@ -54,38 +57,34 @@ func (oc *OfficerController) GetAllOfficers(c *gin.Context) {
 
 // GetOfficerByID godoc
 // @Summary Get officer by ID
-// @Description Retrieves a specific officer by their ID with unit information
+// @Description Retrieves a specific officer by their ID with their unit and the score kept in each subject
 // @Tags Officers
 // @Accept json
 // @Produce json
 // @Param id path int true "Officer ID"
 // @Success 200 {object} OfficerResponse "Officer information with unit details"
-// @Failure 400 {object} map[string]string "Bad request - invalid officer ID"
-// @Failure 404 {object} map[string]string "Officer not found"
-// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 400 {object} ErrorResponse "Bad request - invalid officer ID"
+// @Failure 404 {object} ErrorResponse "Officer not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
 // @Router /api/v1/officers/{id} [get]
 func (oc *OfficerController) GetOfficerByID(c *gin.Context) {
 	// Get officer ID from URL parameter
 	officerIDStr := c.Param("id")
 	officerID, err := strconv.Atoi(officerIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Invalid officer ID format",
-		})
+		abortWithError(c, badRequest("Invalid officer ID format", "Mã cán bộ không hợp lệ"))
 		return
 	}
 
 	// Get officer from service
 	officer, err := oc.contestService.GetOfficerByID(officerID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{
-			"error": err.Error(),
-		})
+		abortWithError(c, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, OfficerResponse{
-		Data:    officer,
+		Data:    model.NewPublicOfficer(officer, oc.contestService.SubjectScores(officer)),
 		Message: "Officer retrieved successfully",
 		Status:  "success",
 	})
@@ -0,0 +1,50 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+func TestPublicOfficersLeaveOutSubmissions(t *testing.T) {
+	api := newTestAPI(t)
+	token := api.login(t, 1, "1234")
+
+	rec := api.do(http.MethodGet, "/api/v1/tests/officer-subject?subjectID=1", token, nil)
+	var test struct {
+		Data struct {
+			ID string `json:"id"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &test); err != nil || test.Data.ID == "" {
+		t.Fatalf("Failed to get test: %d %s", rec.Code, rec.Body.String())
+	}
+	if rec := api.do(http.MethodPost, "/api/v1/tests/start?testID="+test.Data.ID, token, nil); rec.Code != http.StatusOK {
+		t.Fatalf("Failed to start test: %d %s", rec.Code, rec.Body.String())
+	}
+	if rec := api.do(http.MethodPost, "/api/v1/tests/submit?testID="+test.Data.ID, token, map[string]string{}); rec.Code != http.StatusOK {
+		t.Fatalf("Failed to submit: %d %s", rec.Code, rec.Body.String())
+	}
+
+	for _, path := range []string{"/api/v1/officers", "/api/v1/officers/1"} {
+		rec := api.do(http.MethodGet, path, "", nil)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("%s: unexpected status %d", path, rec.Code)
+		}
+		keys := jsonKeys(t, rec.Body.Bytes())
+		for _, key := range []string{"list_submission", "answers", "canonical_answers", "idempotency_key", "payload_hash", "test_id"} {
+			if keys[key] {
+				t.Errorf("%s: public officer payload carries %q: %s", path, key, rec.Body.String())
+			}
+		}
+		if !keys["subjects"] || !keys["subject_name"] {
+			t.Errorf("%s: expected the subject scores, got %s", path, rec.Body.String())
+		}
+	}
+
+	// Submissions stay available to admins
+	rec = api.do(http.MethodGet, "/api/v1/admin/officers/1/submissions", api.login(t, 0, "secret"), nil)
+	if rec.Code != http.StatusOK || !jsonKeys(t, rec.Body.Bytes())["canonical_answers"] {
+		t.Errorf("expected the admin to see the submission, got %d %s", rec.Code, rec.Body.String())
+	}
+}
@@ -33,7 +33,7 @@ type ListSubjectResponse struct {
 // @Accept json
 // @Produce json
 // @Success 200 {object} ListSubjectResponse "success response with subjects list"
-// @Failure 500 {object} map[string]interface{} "internal server error"
+// @Failure 500 {object} ErrorResponse "internal server error"
 // @Router /api/v1/subjects [get]
 func (c *SubjectController) GetAllSubjects(ctx *gin.Context) {
 	subjects := c.contestService.GetAllSubjects()
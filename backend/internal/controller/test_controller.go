@@ -1,12 +1,13 @@
 package controller
 
 import (
+	"errors"
+	"io"
 	"net/http"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/lehaisonagentai3/free-contest/backend/internal/model"
-	_ "github.com/lehaisonagentai3/free-contest/backend/internal/model"
 	"github.com/lehaisonagentai3/free-contest/backend/internal/service"
 )
 
@@ -21,9 +22,9 @@ func NewTestController(contestService *service.ContestService) *TestController {
 }
 
 type TestResponse struct {
-	Data    *model.Test `json:"data,omitempty"`
-	Message string      `json:"message,omitempty"`
-	Status  string      `json:"status,omitempty"`
+	Data    *model.CandidateTest `json:"data,omitempty"`
+	Message string               `json:"message,omitempty"`
+	Status  string               `json:"status,omitempty"`
 }
 
 type SubmissionResponse struct {
@@ -34,72 +35,49 @@ type SubmissionResponse struct {
 
 // GetSubjectTestForOfficer godoc
 // @Summary Get a test for an officer in a specific subject
-// @Description Creates and returns a test for an officer in a specific subject with random questions
+// @Description Creates a test for the logged-in officer in a specific subject with random questions and returns its metadata (subject, duration, number of questions) together with any answers saved so far. The questions themselves are only returned by the start call and GET /api/v1/tests/questions.
 // @Tags Tests
 // @Accept json
 // @Produce json
-// @Param officerID query int true "Officer ID"
+// @Security BearerAuth
 // @Param subjectID query int true "Subject ID"
 // @Success 200 {object} TestResponse "Test created successfully or existing test returned"
-// @Failure 400 {object} map[string]string "Bad request - missing or invalid parameters"
-// @Failure 404 {object} map[string]string "Officer or subject not found"
-// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 400 {object} ErrorResponse "Bad request - missing or invalid parameters"
+// @Failure 401 {object} ErrorResponse "Missing or invalid token"
+// @Failure 404 {object} ErrorResponse "Officer or subject not found"
+// @Failure 409 {object} ErrorResponse "Test expired, retake cooldown has not passed or the subject has not enough questions"
+// @Failure 500 {object} ErrorResponse "Internal server error"
 // @Router /api/v1/tests/officer-subject [get]
 func (tc *TestController) GetSubjectTestForOfficer(c *gin.Context) {
+	// The officer comes from the session token
+	officerID := c.GetInt(contextOfficerID)
+
 	// Get query parameters
-	officerIDStr := c.Query("officerID")
 	subjectIDStr := c.Query("subjectID")
 
 	// Validate parameters
-	if officerIDStr == "" || subjectIDStr == "" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Both officerID and subjectID query parameters are required",
-		})
-		return
-	}
-
-	// Parse officerID
-	officerID, err := strconv.Atoi(officerIDStr)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Invalid officerID: must be a valid integer",
-		})
+	if subjectIDStr == "" {
+		abortWithError(c, badRequest("subjectID query parameter is required", "Thiếu tham số subjectID"))
 		return
 	}
 
 	// Parse subjectID
 	subjectID, err := strconv.Atoi(subjectIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Invalid subjectID: must be a valid integer",
-		})
+		abortWithError(c, badRequest("Invalid subjectID: must be a valid integer", "subjectID phải là số nguyên"))
 		return
 	}
 
 	// Call service to get the test
 	test, err := tc.contestService.GetSubjectTestForOfficer(officerID, subjectID)
 	if err != nil {
-		// Handle different types of errors
-		switch err.Error() {
-		case "officer not found":
-			c.JSON(http.StatusNotFound, gin.H{
-				"error": err.Error(),
-			})
-		case "subject not found":
-			c.JSON(http.StatusNotFound, gin.H{
-				"error": err.Error(),
-			})
-		default:
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"error": err.Error(),
-			})
-		}
+		abortWithError(c, err)
 		return
 	}
 
 	// Return the test
 	c.JSON(http.StatusOK, TestResponse{
-		Data:    test,
+		Data:    model.NewCandidateTest(test),
 		Message: "Test retrieved successfully",
 		Status:  "success",
 	})
@@ -107,186 +85,205 @@ func (tc *TestController) GetSubjectTestForOfficer(c *gin.Context) {
 
 // StartTest godoc
 // @Summary Start a test for an officer
-// @Description Starts a test by setting the start time for the specified officer and test
+// @Description Starts a test of the logged-in officer by setting its start time and returns it with its questions
 // @Tags Tests
 // @Accept json
 // @Produce json
-// @Param officerID query int true "Officer ID"
-// @Param testID query int true "Test ID"
+// @Security BearerAuth
+// @Param testID query string true "Test ID"
 // @Success 200 {object} TestResponse "Test started successfully"
-// @Failure 400 {object} map[string]string "Bad request - missing or invalid parameters"
-// @Failure 404 {object} map[string]string "Test not found"
-// @Failure 409 {object} map[string]string "Test already started"
-// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 400 {object} ErrorResponse "Bad request - missing or invalid parameters"
+// @Failure 401 {object} ErrorResponse "Missing or invalid token"
+// @Failure 404 {object} ErrorResponse "Test not found"
+// @Failure 409 {object} ErrorResponse "Test already started"
+// @Failure 500 {object} ErrorResponse "Internal server error"
 // @Router /api/v1/tests/start [post]
 func (tc *TestController) StartTest(c *gin.Context) {
+	// The officer comes from the session token
+	officerID := c.GetInt(contextOfficerID)
+
 	// Get query parameters
-	officerIDStr := c.Query("officerID")
-	testIDStr := c.Query("testID")
+	testID := c.Query("testID")
 
 	// Validate parameters
-	if officerIDStr == "" || testIDStr == "" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Both officerID and testID query parameters are required",
-		})
+	if testID == "" {
+		abortWithError(c, badRequest("testID query parameter is required", "Thiếu tham số testID"))
 		return
 	}
 
-	// Parse officerID
-	officerID, err := strconv.Atoi(officerIDStr)
+	// Call service to start the test
+	test, err := tc.contestService.StartTest(officerID, testID)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Invalid officerID: must be a valid integer",
-		})
+		abortWithError(c, err)
 		return
 	}
 
-	// Parse testID
-	testID, err := strconv.Atoi(testIDStr)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Invalid testID: must be a valid integer",
-		})
+	// Return the started test
+	c.JSON(http.StatusOK, TestResponse{
+		Data:    model.NewCandidateTest(test).WithQuestions(test),
+		Message: "Test started successfully",
+		Status:  "success",
+	})
+}
+
+// GetTestQuestions godoc
+// @Summary Get the questions of a started test
+// @Description Returns a started test of the logged-in officer with its questions, for instance to resume after a refresh. Only available until the deadline plus the grace period.
+// @Tags Tests
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param testID query string true "Test ID"
+// @Success 200 {object} TestResponse "Test with its questions"
+// @Failure 400 {object} ErrorResponse "Bad request - missing parameters"
+// @Failure 401 {object} ErrorResponse "Missing or invalid token"
+// @Failure 404 {object} ErrorResponse "Test not found"
+// @Failure 409 {object} ErrorResponse "Test not started, already submitted, paused or expired"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /api/v1/tests/questions [get]
+func (tc *TestController) GetTestQuestions(c *gin.Context) {
+	// The officer comes from the session token
+	officerID := c.GetInt(contextOfficerID)
+
+	testID := c.Query("testID")
+	if testID == "" {
+		abortWithError(c, badRequest("testID query parameter is required", "Thiếu tham số testID"))
 		return
 	}
 
-	// Call service to start the test
-	test, err := tc.contestService.StartTest(officerID, testID)
+	test, err := tc.contestService.GetTestQuestions(officerID, testID)
 	if err != nil {
-		// Handle different types of errors
-		switch err.Error() {
-		case "no tests found":
-			c.JSON(http.StatusNotFound, gin.H{
-				"error": "No tests found",
-			})
-		case "no tests found for officer":
-			c.JSON(http.StatusNotFound, gin.H{
-				"error": "No tests found for officer",
-			})
-		case "test not found":
-			c.JSON(http.StatusNotFound, gin.H{
-				"error": "Test not found",
-			})
-		case "test already started":
-			c.JSON(http.StatusConflict, gin.H{
-				"error": "Test already started",
-			})
-		default:
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"error": err.Error(),
-			})
-		}
+		abortWithError(c, err)
 		return
 	}
 
-	// Return the started test
 	c.JSON(http.StatusOK, TestResponse{
-		Data:    test,
-		Message: "Test started successfully",
+		Data:    model.NewCandidateTest(test).WithQuestions(test),
+		Message: "Questions retrieved successfully",
 		Status:  "success",
 	})
 }
 
 // SubmitTest godoc
 // @Summary Submit test answers
-// @Description Submit answers for a test and get the calculated score
+// @Description Submit answers for a test and get the calculated score. Every key must be a question of the test and every non-blank value one of its option letters, otherwise nothing is recorded and the response lists each rejected entry. Submitted answers override the ones saved with PUT /api/v1/tests/answers, and an empty body submits the saved answers as they are. Questions left without an answer are recorded as unanswered (""). The server clock decides whether the answers count: after the deadline plus the grace period the submission is recorded as late and only the answers saved in time are graded.
 // @Tags Tests
 // @Accept json
 // @Produce json
-// @Param officerID query int true "Officer ID"
-// @Param testID query int true "Test ID"
-// @Param submission body map[string]string true "Question ID to answer mapping"
+// @Security BearerAuth
+// @Param testID query string true "Test ID"
+// @Param Idempotency-Key header string false "Client key of the request, a retry with the same key and answers returns the original submission"
+// @Param submission body map[string]string false "Question ID to answer mapping"
 // @Success 200 {object} SubmissionResponse "Test submitted successfully with score"
-// @Failure 400 {object} map[string]string "Bad request - missing or invalid parameters"
-// @Failure 404 {object} map[string]string "Test not found"
-// @Failure 409 {object} map[string]string "Test already submitted or not started"
-// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 400 {object} ErrorResponse "Bad request - missing parameters or invalid answers"
+// @Failure 401 {object} ErrorResponse "Missing or invalid token"
+// @Failure 404 {object} ErrorResponse "Test not found"
+// @Failure 409 {object} ErrorResponse "Test already submitted, not started or paused, or idempotency key reused"
+// @Failure 500 {object} ErrorResponse "Internal server error"
 // @Router /api/v1/tests/submit [post]
 func (tc *TestController) SubmitTest(c *gin.Context) {
+	// The officer comes from the session token
+	officerID := c.GetInt(contextOfficerID)
+
 	// Get query parameters
-	officerIDStr := c.Query("officerID")
-	testIDStr := c.Query("testID")
+	testID := c.Query("testID")
 
 	// Validate parameters
-	if officerIDStr == "" || testIDStr == "" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Both officerID and testID query parameters are required",
-		})
+	if testID == "" {
+		abortWithError(c, badRequest("testID query parameter is required", "Thiếu tham số testID"))
 		return
 	}
 
-	// Parse officerID
-	officerID, err := strconv.Atoi(officerIDStr)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Invalid officerID: must be a valid integer",
-		})
+	// Parse request body for answers, an empty body keeps the saved answers
+	var answers map[string]string
+	if err := c.ShouldBindJSON(&answers); err != nil && !errors.Is(err, io.EOF) {
+		abortWithError(c, badRequest("Invalid request body: must be a JSON object with question IDs as keys and answers as values", "Dữ liệu gửi lên phải là đối tượng JSON với khóa là mã câu hỏi và giá trị là đáp án"))
 		return
 	}
 
-	// Parse testID
-	testID, err := strconv.Atoi(testIDStr)
+	// Retries carry the key of the original request
+	key, ok := idempotencyKey(c)
+	if !ok {
+		return
+	}
+
+	// Call service to submit the test
+	submission, err := tc.contestService.SubmitTest(officerID, testID, answers, key)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Invalid testID: must be a valid integer",
-		})
+		abortWithError(c, err)
+		return
+	}
+
+	message := "Submission successful"
+	if submission.Status == model.SubmissionStatusLate {
+		message = "Submission received after the deadline, only answers saved in time were graded"
+	}
+
+	// Return the submission result
+	c.JSON(http.StatusOK, SubmissionResponse{
+		Data:    submission,
+		Message: message,
+		Status:  "success",
+	})
+}
+
+type SavedAnswersResponse struct {
+	Data    map[string]model.SavedAnswer `json:"data,omitempty"`
+	Message string                       `json:"message,omitempty"`
+	Status  string                       `json:"status,omitempty"`
+}
+
+// SaveAnswers godoc
+// @Summary Save answers without submitting
+// @Description Saves one or more answers of a started test so they survive a refresh or a crash. A blank answer clears the question. The saved answers are returned with the test and graded on submission or auto-submission.
+// @Tags Tests
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param testID query string true "Test ID"
+// @Param Idempotency-Key header string false "Client key of the request, a retry with the same key and answers is not applied again"
+// @Param answers body map[string]string true "Question ID to answer mapping"
+// @Success 200 {object} SavedAnswersResponse "Every answer saved so far"
+// @Failure 400 {object} ErrorResponse "Bad request - missing parameters or invalid answers"
+// @Failure 401 {object} ErrorResponse "Missing or invalid token"
+// @Failure 404 {object} ErrorResponse "Test not found"
+// @Failure 409 {object} ErrorResponse "Test not started, already submitted, paused or expired, or idempotency key reused"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /api/v1/tests/answers [put]
+func (tc *TestController) SaveAnswers(c *gin.Context) {
+	// The officer comes from the session token
+	officerID := c.GetInt(contextOfficerID)
+
+	testID := c.Query("testID")
+	if testID == "" {
+		abortWithError(c, badRequest("testID query parameter is required", "Thiếu tham số testID"))
 		return
 	}
 
-	// Parse request body for answers
 	var answers map[string]string
 	if err := c.ShouldBindJSON(&answers); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Invalid request body: must be a JSON object with question IDs as keys and answers as values",
-		})
+		abortWithError(c, badRequest("Invalid request body: must be a JSON object with question IDs as keys and answers as values", "Dữ liệu gửi lên phải là đối tượng JSON với khóa là mã câu hỏi và giá trị là đáp án"))
 		return
 	}
-
-	// Validate that answers is not empty
 	if len(answers) == 0 {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Answers cannot be empty",
-		})
+		abortWithError(c, badRequest("Answers cannot be empty", "Danh sách đáp án không được để trống"))
 		return
 	}
 
-	// Call service to submit the test
-	submission, err := tc.contestService.SubmitTest(officerID, testID, answers)
+	key, ok := idempotencyKey(c)
+	if !ok {
+		return
+	}
+
+	saved, err := tc.contestService.SaveAnswers(officerID, testID, answers, key)
 	if err != nil {
-		// Handle different types of errors
-		switch err.Error() {
-		case "no tests found":
-			c.JSON(http.StatusNotFound, gin.H{
-				"error": "No tests found",
-			})
-		case "no tests found for officer":
-			c.JSON(http.StatusNotFound, gin.H{
-				"error": "No tests found for officer",
-			})
-		case "test not found":
-			c.JSON(http.StatusNotFound, gin.H{
-				"error": "Test not found",
-			})
-		case "test has not been started yet":
-			c.JSON(http.StatusConflict, gin.H{
-				"error": "Test has not been started yet",
-			})
-		case "test has already been submitted":
-			c.JSON(http.StatusConflict, gin.H{
-				"error": "Test has already been submitted",
-			})
-		default:
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"error": err.Error(),
-			})
-		}
+		abortWithError(c, err)
 		return
 	}
 
-	// Return the submission result
-	c.JSON(http.StatusOK, SubmissionResponse{
-		Data:    submission,
-		Message: "Submission successful",
+	c.JSON(http.StatusOK, SavedAnswersResponse{
+		Data:    saved,
+		Message: "Answers saved",
 		Status:  "success",
 	})
 }
@@ -0,0 +1,86 @@
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestCandidatePayloadsLeaveOutTheAnswerKey(t *testing.T) {
+	api := newTestAPI(t)
+	token := api.login(t, 1, "1234")
+
+	// Every response an officer gets while taking a test
+	check := func(name string, status, want int, body []byte) {
+		t.Helper()
+		if status != want {
+			t.Fatalf("%s: unexpected status %d: %s", name, status, body)
+		}
+		keys := jsonKeys(t, body)
+		for _, key := range []string{"correct", "option_order", "seed", "bank_version"} {
+			if keys[key] {
+				t.Errorf("%s: candidate payload carries %q: %s", name, key, body)
+			}
+		}
+	}
+
+	rec := api.do(http.MethodGet, "/api/v1/tests/officer-subject?subjectID=1", token, nil)
+	check("officer-subject", rec.Code, http.StatusOK, rec.Body.Bytes())
+	var test struct {
+		Data struct {
+			ID string `json:"id"`
+		} `json:"data"`
+	}
+	json.Unmarshal(rec.Body.Bytes(), &test)
+
+	rec = api.do(http.MethodPost, "/api/v1/tests/start?testID="+test.Data.ID, token, nil)
+	check("start", rec.Code, http.StatusOK, rec.Body.Bytes())
+
+	rec = api.do(http.MethodGet, "/api/v1/tests/questions?testID="+test.Data.ID, token, nil)
+	check("questions", rec.Code, http.StatusOK, rec.Body.Bytes())
+	var started struct {
+		Data struct {
+			Questions []struct {
+				ID      int    `json:"id"`
+				Content string `json:"content"`
+				AnswerA string `json:"answer_a"`
+			} `json:"questions"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &started); err != nil || len(started.Data.Questions) != 4 {
+		t.Fatalf("expected the 4 questions of the test, got %s", rec.Body.String())
+	}
+	for _, question := range started.Data.Questions {
+		if question.Content == "" || question.AnswerA == "" {
+			t.Errorf("expected the question text and options, got %+v", question)
+		}
+	}
+
+	questionID := fmt.Sprint(started.Data.Questions[0].ID)
+	rec = api.do(http.MethodPut, "/api/v1/tests/answers?testID="+test.Data.ID, token, map[string]string{questionID: "A"})
+	check("answers", rec.Code, http.StatusOK, rec.Body.Bytes())
+
+	rec = api.do(http.MethodPost, "/api/v1/tests/submit?testID="+test.Data.ID, token, map[string]string{})
+	check("submit", rec.Code, http.StatusOK, rec.Body.Bytes())
+}
+
+func TestAdminTestCarriesTheAnswerKey(t *testing.T) {
+	api := newTestAPI(t)
+	officerToken := api.login(t, 1, "1234")
+	if rec := api.do(http.MethodGet, "/api/v1/tests/officer-subject?subjectID=1", officerToken, nil); rec.Code != http.StatusOK {
+		t.Fatalf("Failed to get test: %d %s", rec.Code, rec.Body.String())
+	}
+
+	path := "/api/v1/admin/tests/officer-subject?officerID=1&subjectID=1"
+	if rec := api.do(http.MethodGet, path, "", nil); rec.Code != http.StatusUnauthorized {
+		t.Errorf("expected 401 without a token, got %d", rec.Code)
+	}
+	if rec := api.do(http.MethodGet, path, officerToken, nil); rec.Code != http.StatusForbidden {
+		t.Errorf("expected 403 for an officer token, got %d", rec.Code)
+	}
+	rec := api.do(http.MethodGet, path, api.login(t, 0, "secret"), nil)
+	if rec.Code != http.StatusOK || !jsonKeys(t, rec.Body.Bytes())["correct"] {
+		t.Errorf("expected the test with its answer key, got %d %s", rec.Code, rec.Body.String())
+	}
+}
@@ -32,7 +32,7 @@ type ListUnitResponse struct {
 // @Accept json
 // @Produce json
 // @Success 200 {array} ListUnitResponse "List of units"
-// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 500 {object} ErrorResponse "Internal server error"
 // @Router /api/v1/units [get]
 func (uc *UnitController) GetAllUnits(c *gin.Context) {
 	units := uc.contestService.GetAllUnits()
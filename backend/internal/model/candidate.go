@@ -0,0 +1,100 @@
+package model
+
+// CandidateTest is the view of a Test sent to the officer taking it. It never
+// carries the answer key, and carries the questions only once the test is
+// started.
+type CandidateTest struct {
+	ID            string                 `json:"id,omitempty"`
+	Name          string                 `json:"name,omitempty"`
+	ContestID     string                 `json:"contest_id,omitempty"`
+	Duration      int                    `json:"duration,omitempty"`   // in seconds
+	ExtraTime     int                    `json:"extra_time,omitempty"` // seconds of Duration granted by the officer's accommodation
+	Subject       *Subject               `json:"subject,omitempty"`
+	Officer       *Officer               `json:"officer,omitempty"`
+	Invalidated   bool                   `json:"invalidated,omitempty"` // an admin invalidated the test
+	Paused        bool                   `json:"paused,omitempty"`      // an admin paused the timer
+	NumQuestions  int                    `json:"num_questions,omitempty"`
+	Questions     []*CandidateQuestion   `json:"questions,omitempty"`
+	RemainingTime int                    `json:"remaining_time,omitempty"` // time left for the test in seconds
+	IsFinished    bool                   `json:"is_finished,omitempty"`
+	StartTime     int64                  `json:"start_time,omitempty"` // timestamp when the test started
+	Attempt       int                    `json:"attempt,omitempty"`
+	SavedAnswers  map[string]SavedAnswer `json:"saved_answers,omitempty"` // answers saved so far, to resume after a refresh
+}
+
+// CandidateQuestion is a Question without its correct answer
+type CandidateQuestion struct {
+	ID      int    `json:"id,omitempty"`
+	Content string `json:"content,omitempty"`
+	AnswerA string `json:"answer_a,omitempty"`
+	AnswerB string `json:"answer_b,omitempty"`
+	AnswerC string `json:"answer_c,omitempty"`
+	AnswerD string `json:"answer_d,omitempty"`
+}
+
+// NewCandidateTest returns the metadata of a test without its questions, see
+// WithQuestions
+func NewCandidateTest(test *Test) *CandidateTest {
+	if test == nil {
+		return nil
+	}
+	view := &CandidateTest{
+		ID:            test.ID,
+		Name:          test.Name,
+		ContestID:     test.ContestID,
+		Duration:      test.Duration,
+		ExtraTime:     test.ExtraTime,
+		RemainingTime: test.RemainingTime,
+		IsFinished:    test.IsFinished,
+		StartTime:     test.StartTime,
+		Attempt:       test.Attempt,
+		SavedAnswers:  test.SavedAnswers,
+		NumQuestions:  len(test.Questions),
+		Invalidated:   test.InvalidatedAt > 0,
+		Paused:        test.PausedAt > 0,
+	}
+	if test.Subject != nil {
+		view.Subject = &Subject{
+			ID:              test.Subject.ID,
+			Name:            test.Subject.Name,
+			Description:     test.Subject.Description,
+			ContestID:       test.Subject.ContestID,
+			NumQuestionTest: test.Subject.NumQuestionTest,
+			TestTime:        test.Subject.TestTime,
+		}
+	}
+	if test.Officer != nil {
+		// Other submissions of the officer are not part of the test
+		view.Officer = &Officer{
+			ID:       test.Officer.ID,
+			Name:     test.Officer.Name,
+			Unit:     test.Officer.Unit,
+			Rank:     test.Officer.Rank,
+			Position: test.Officer.Position,
+		}
+	}
+	return view
+}
+
+// WithQuestions adds the questions of a started test to its view
+func (view *CandidateTest) WithQuestions(test *Test) *CandidateTest {
+	if view == nil {
+		return nil
+	}
+	view.Questions = make([]*CandidateQuestion, 0, len(test.Questions))
+	for _, question := range test.Questions {
+		view.Questions = append(view.Questions, NewCandidateQuestion(question))
+	}
+	return view
+}
+
+func NewCandidateQuestion(question *Question) *CandidateQuestion {
+	return &CandidateQuestion{
+		ID:      question.ID,
+		Content: question.Content,
+		AnswerA: question.AnswerA,
+		AnswerB: question.AnswerB,
+		AnswerC: question.AnswerC,
+		AnswerD: question.AnswerD,
+	}
+}
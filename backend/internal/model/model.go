@@ -1,5 +1,7 @@
 package model
 
+import "strings"
+
 type Officer struct {
 	ID             int           `json:"id,omitempty"`
 	Name           string        `json:"name,omitempty"`
@@ -7,6 +9,7 @@ type Officer struct {
 	Score          float32       `json:"score,omitempty"`
 	Rank           string        `json:"rank,omitempty"`
 	Position       string        `json:"position,omitempty"`
+	PIN            string        `json:"-"`                         // login PIN, never serialized
 	ListSubmission []*Submission `json:"list_submission,omitempty"` // list of submissions made by the officer
 }
 
@@ -19,16 +22,115 @@ type Contest struct {
 }
 
 type Test struct {
-	ID            int         `json:"id,omitempty"`
+	ID            string      `json:"id,omitempty"`
 	Name          string      `json:"name,omitempty"`
 	ContestID     string      `json:"contest_id,omitempty"`
-	Duration      int         `json:"duration,omitempty"` // in seconds
+	Duration      int         `json:"duration,omitempty"`   // in seconds
+	ExtraTime     int         `json:"extra_time,omitempty"` // seconds of Duration granted by the officer's accommodation
 	Subject       *Subject    `json:"subject,omitempty"`
 	Officer       *Officer    `json:"officer,omitempty"`
 	Questions     []*Question `json:"questions,omitempty"`      // list of questions in the test
-	RemainingTime int         `json:"remaining_time,omitempty"` // time left for the test in seconds
+	RemainingTime int         `json:"remaining_time,omitempty"` // time left for the test in seconds when it was read
 	IsFinished    bool        `json:"is_finished,omitempty"`    // whether the test is finished
 	StartTime     int64       `json:"start_time,omitempty"`     // timestamp when the test started
+	Seed          uint64      `json:"seed,omitempty,string"`    // seed the questions and option order were drawn with
+	BankVersion   string      `json:"bank_version,omitempty"`   // fingerprint of the subject's question bank at generation time
+	Attempt       int         `json:"attempt,omitempty"`        // 1 for the first test of the officer in the subject, 2 for the first retake...
+	FinishedAt    int64       `json:"finished_at,omitempty"`    // timestamp when the test was submitted
+	// ExcludedQuestionIDs are the questions seen in earlier attempts, which the
+	// generator avoided where the bank allowed. Needed to regenerate the paper.
+	ExcludedQuestionIDs []int `json:"excluded_question_ids,omitempty"`
+	// SavedAnswers are the answers saved while the test is in progress, by
+	// question ID. They are graded when the test is submitted or auto-submitted.
+	SavedAnswers map[string]SavedAnswer `json:"saved_answers,omitempty"`
+	// LastSaveKey is the idempotency key of the last answer save and
+	// LastSaveHash the fingerprint of its answers, to recognize a retry. The
+	// client sends one save at a time, so only the last one can be retried.
+	// Both are cleared when the test finishes.
+	LastSaveKey   string `json:"last_save_key,omitempty"`
+	LastSaveHash  string `json:"last_save_hash,omitempty"`
+	InvalidatedAt int64  `json:"invalidated_at,omitempty"` // timestamp an admin invalidated the test, 0 if valid
+	InvalidatedBy string `json:"invalidated_by,omitempty"` // admin who invalidated the test
+	InvalidReason string `json:"invalid_reason,omitempty"` // why the test was invalidated
+	PausedAt      int64  `json:"paused_at,omitempty"`      // timestamp an admin paused the timer, 0 while it runs
+	PausedFor     int    `json:"paused_for,omitempty"`     // seconds spent paused before the last resume, added to the deadline
+	// ResetAt is set when an admin discarded the test. A reset test is kept on
+	// disk for the record but is no longer an attempt of the officer.
+	ResetAt int64 `json:"reset_at,omitempty"`
+}
+
+// SavedAnswer is an answer saved before submission
+type SavedAnswer struct {
+	Answer  string `json:"answer"`   // letter as displayed to the officer, "" when cleared
+	SavedAt int64  `json:"saved_at"` // timestamp of the save
+}
+
+// TestAudit compares a stored test with the paper regenerated from its seed
+type TestAudit struct {
+	Test               *Test       `json:"test"`
+	Regenerated        []*Question `json:"regenerated"`          // questions generated again from the recorded seed
+	CurrentBankVersion string      `json:"current_bank_version"` // fingerprint of the bank loaded now
+	BankChanged        bool        `json:"bank_changed"`         // the bank was edited since the test was generated
+	Matches            bool        `json:"matches"`              // the regenerated paper is identical to the stored one
+}
+
+// Deadline returns the timestamp after which answers are no longer accepted,
+// or 0 if the test has not been started. Time spent paused pushes it back;
+// the pause in progress, if any, is only added on resume.
+func (t *Test) Deadline() int64 {
+	if t.StartTime == 0 {
+		return 0
+	}
+	return t.StartTime + int64(t.Duration) + int64(t.PausedFor)
+}
+
+// Remaining returns the seconds left at now: the full duration before the
+// test is started, the time left when it was paused while it is paused, 0
+// once it is finished or past its deadline
+func (t *Test) Remaining(now int64) int {
+	if t.PausedAt > 0 {
+		now = t.PausedAt
+	}
+	switch {
+	case t.IsFinished:
+		return 0
+	case t.StartTime == 0:
+		return t.Duration
+	case now >= t.Deadline():
+		return 0
+	default:
+		return int(t.Deadline() - now)
+	}
+}
+
+// TestProgress summarizes a test in progress for proctors
+type TestProgress struct {
+	TestID        string `json:"test_id"`
+	OfficerID     int    `json:"officer_id"`
+	OfficerName   string `json:"officer_name"`
+	SubjectID     int    `json:"subject_id"`
+	SubjectName   string `json:"subject_name"`
+	Attempt       int    `json:"attempt,omitempty"`
+	StartTime     int64  `json:"start_time"`
+	Deadline      int64  `json:"deadline"`
+	RemainingTime int    `json:"remaining_time"` // seconds left until the deadline
+	NumQuestions  int    `json:"num_questions"`
+	NumAnswered   int    `json:"num_answered"`        // questions with a saved answer
+	PausedAt      int64  `json:"paused_at,omitempty"` // timestamp the timer was paused, 0 while it runs
+}
+
+// TestClock is the server's view of the time, for clients to correct their
+// timers against
+type TestClock struct {
+	ServerTime    int64  `json:"server_time"`            // Unix seconds
+	ServerTimeMs  int64  `json:"server_time_ms"`         // Unix milliseconds
+	TestID        string `json:"test_id,omitempty"`      // set when asked for a test
+	StartTime     int64  `json:"start_time,omitempty"`   // timestamp when the test started
+	Deadline      int64  `json:"deadline,omitempty"`     // timestamp the test has to be submitted by
+	RemainingTime int    `json:"remaining_time"`         // seconds left until the deadline
+	GracePeriod   int    `json:"grace_period,omitempty"` // seconds after the deadline during which answers still count
+	IsFinished    bool   `json:"is_finished,omitempty"`  // the test is already submitted
+	Paused        bool   `json:"paused,omitempty"`       // an admin paused the timer, the remaining time does not decrease
 }
 
 type Question struct {
@@ -39,6 +141,42 @@ type Question struct {
 	AnswerC string `json:"answer_c,omitempty"`
 	AnswerD string `json:"answer_d,omitempty"`
 	Correct string `json:"correct,omitempty"` // correct answer (A, B, C, or D)
+	// OptionOrder is set on questions of a generated test whose options were
+	// shuffled: OptionOrder[i] is the original letter of the option displayed
+	// at position i. Correct always keeps the original letter.
+	OptionOrder []string `json:"option_order,omitempty"`
+}
+
+// OptionLetters are the letters of the answer options in display order
+var OptionLetters = []string{"A", "B", "C", "D"}
+
+// CanonicalLetter maps a letter chosen by the officer to the original letter
+// of that option in the question bank, or "" if no option is shown there
+func (q *Question) CanonicalLetter(displayed string) string {
+	displayed = strings.ToUpper(strings.TrimSpace(displayed))
+	if len(q.OptionOrder) == 0 {
+		return displayed
+	}
+	for i, letter := range q.OptionOrder {
+		if OptionLetters[i] == displayed {
+			return letter
+		}
+	}
+	return ""
+}
+
+// DisplayedLetter maps an original letter to the letter the officer sees
+func (q *Question) DisplayedLetter(canonical string) string {
+	canonical = strings.ToUpper(strings.TrimSpace(canonical))
+	if len(q.OptionOrder) == 0 {
+		return canonical
+	}
+	for i, letter := range q.OptionOrder {
+		if letter == canonical {
+			return OptionLetters[i]
+		}
+	}
+	return ""
 }
 
 type Subject struct {
@@ -50,29 +188,109 @@ type Subject struct {
 	NumQuestionTest int        `json:"num_question_test,omitempty"` // number of questions of subject in the test
 	TestTime        int        `json:"test_time,omitempty"`         // time limit for the test in minutes
 	FolderPath      string     `json:"folder_path,omitempty"`       // path to the folder containing questions
+	Order           int        `json:"order,omitempty"`             // position among the subjects, from subject.json
+	// Retake policy from subject.json, used unless config.json sets one for
+	// the subject
+	RetakePolicy *SubjectRetakePolicy `json:"-"`
+}
+
+// SubjectRetakePolicy is the retake policy of a subject's manifest, with the
+// fields of config.RetakePolicy. Fields left out keep the config's default.
+type SubjectRetakePolicy struct {
+	MaxAttempts int    `json:"max_attempts,omitempty"`
+	Cooldown    int    `json:"cooldown,omitempty"`
+	Scoring     string `json:"scoring,omitempty"`
 }
 
 type Chapter struct {
 	ID              int         `json:"id,omitempty"`
 	SubjectID       int         `json:"subject_id,omitempty"`
 	Name            string      `json:"name,omitempty"`
+	Description     string      `json:"description,omitempty"`
 	Questions       []*Question `json:"questions,omitempty"`
 	NumQuestionTest int         `json:"num_question_test,omitempty"` // number of questions of chapter in the test
 	FolderPath      string      `json:"folder_path,omitempty"`       // path to the folder containing questions
 	TotalQuestions  int         `json:"total_questions"`             // total number of questions in the chapter
+	Order           int         `json:"order,omitempty"`             // position among the chapters, from chapter.json
 }
 
 type Submission struct {
-	ID          int               `json:"id,omitempty"`
-	OfficerID   int               `json:"officer_id,omitempty"`
-	TestID      int               `json:"test_id,omitempty"`
-	Answers     map[string]string `json:"answers,omitempty"` // question ID to answer mapping
-	Score       float32           `json:"score,omitempty"`
-	SubmittedAt int64             `json:"submitted_at,omitempty"` // timestamp of submission
-	SubjectID   int               `json:"subject_id,omitempty"`   // ID of the subject for which the test was taken
-	SubjectName string            `json:"subject_name,omitempty"` // name of the subject for which the test was taken
+	ID        string            `json:"id,omitempty"`
+	OfficerID int               `json:"officer_id,omitempty"`
+	TestID    string            `json:"test_id,omitempty"`
+	Answers   map[string]string `json:"answers,omitempty"` // question ID to answer mapping, letters as displayed to the officer, "" when unanswered
+	// CanonicalAnswers holds the same answers mapped back to the letters of the
+	// question bank, which is what the score is computed from. Every question of
+	// the test has an entry.
+	CanonicalAnswers map[string]string `json:"canonical_answers,omitempty"`
+	Score            float32           `json:"score,omitempty"`
+	SubmittedAt      int64             `json:"submitted_at,omitempty"`    // timestamp of submission
+	SubjectID        int               `json:"subject_id,omitempty"`      // ID of the subject for which the test was taken
+	SubjectName      string            `json:"subject_name,omitempty"`    // name of the subject for which the test was taken
+	Deadline         int64             `json:"deadline,omitempty"`        // timestamp the test had to be submitted by
+	Attempt          int               `json:"attempt,omitempty"`         // attempt number of the test
+	Status           string            `json:"status,omitempty"`          // how the submission was accepted, see SubmissionStatus*
+	IdempotencyKey   string            `json:"idempotency_key,omitempty"` // key the client sent with the submit request
+	PayloadHash      string            `json:"payload_hash,omitempty"`    // fingerprint of the submitted answers, to recognize retries
+	Invalidated      bool              `json:"invalidated,omitempty"`     // the test was invalidated, the score does not count
+	VoidedAt         int64             `json:"voided_at,omitempty"`       // timestamp an admin voided the submission, the score does not count
+	VoidedBy         string            `json:"voided_by,omitempty"`       // admin who voided the submission
+	VoidReason       string            `json:"void_reason,omitempty"`     // why the submission was voided
+}
+
+// Counts reports whether the score of the submission counts toward the
+// officer's total
+func (s *Submission) Counts() bool {
+	return !s.Invalidated && s.VoidedAt == 0
 }
 
+const (
+	SubmissionStatusOnTime  = "on_time"        // submitted before the deadline
+	SubmissionStatusInGrace = "in_grace"       // submitted after the deadline but within the grace period, graded normally
+	SubmissionStatusLate    = "late"           // submitted after the grace period, only the answers saved in time were graded
+	SubmissionStatusAuto    = "auto_submitted" // never submitted, finalized by the server with the saved answers once the grace period was over
+)
+
+// Accommodation grants an officer extra time in one subject or, with
+// SubjectID 0, in every subject
+type Accommodation struct {
+	OfficerID    int    `json:"officer_id"`
+	SubjectID    int    `json:"subject_id,omitempty"`    // 0 for every subject
+	ExtraMinutes int    `json:"extra_minutes,omitempty"` // minutes added to the subject's test time
+	ExtraPercent int    `json:"extra_percent,omitempty"` // percentage of the subject's test time added on top
+	Note         string `json:"note,omitempty"`          // for instance the reference of the approval
+}
+
+// ExtraSeconds returns the time the accommodation adds to a test of the given
+// duration in seconds
+func (a *Accommodation) ExtraSeconds(duration int) int {
+	return a.ExtraMinutes*60 + duration*a.ExtraPercent/100
+}
+
+// AdminAction is an entry of the audit trail of admin operations on tests,
+// submissions and accommodations
+type AdminAction struct {
+	ID           string `json:"id"`
+	Action       string `json:"action"` // see AdminAction*
+	Admin        string `json:"admin"`  // username of the admin
+	OfficerID    int    `json:"officer_id"`
+	TestID       string `json:"test_id,omitempty"`
+	SubmissionID string `json:"submission_id,omitempty"`
+	Reason       string `json:"reason,omitempty"`
+	Detail       string `json:"detail,omitempty"` // what changed, for instance the minutes added
+	At           int64  `json:"at"`               // timestamp of the action
+}
+
+const (
+	AdminActionExtend      = "extend"
+	AdminActionPause       = "pause"
+	AdminActionResume      = "resume"
+	AdminActionReset       = "reset"
+	AdminActionVoid        = "void"
+	AdminActionInvalidate  = "invalidate"
+	AdminActionAccommodate = "accommodate"
+)
+
 type ContestMetaInfo struct {
 	RootPath string   `json:"root_path,omitempty"` // root path of the contest
 	Contest  *Contest `json:"contest,omitempty"`   // contest information
@@ -0,0 +1,44 @@
+package model
+
+// PublicOfficer is the view of an officer served without an admin login:
+// who the officer is and the scores kept, never the submissions themselves,
+// which carry the answers
+type PublicOfficer struct {
+	ID       int             `json:"id,omitempty"`
+	Name     string          `json:"name,omitempty"`
+	Rank     string          `json:"rank,omitempty"`
+	Position string          `json:"position,omitempty"`
+	Unit     string          `json:"unit,omitempty"`
+	Score    float32         `json:"score,omitempty"`    // total of the subject scores
+	Subjects []*SubjectScore `json:"subjects,omitempty"` // subjects with a counted submission
+}
+
+// SubjectScore is the score an officer keeps in a subject under its retake
+// policy
+type SubjectScore struct {
+	SubjectID   int     `json:"subject_id"`
+	SubjectName string  `json:"subject_name"`
+	Score       float32 `json:"score"`
+	Attempts    int     `json:"attempts"`     // counted submissions
+	SubmittedAt int64   `json:"submitted_at"` // time of the last counted submission
+}
+
+// NewPublicOfficer returns the public view of an officer with the scores
+// kept in each subject
+func NewPublicOfficer(officer *Officer, subjects []*SubjectScore) *PublicOfficer {
+	if officer == nil {
+		return nil
+	}
+	view := &PublicOfficer{
+		ID:       officer.ID,
+		Name:     officer.Name,
+		Rank:     officer.Rank,
+		Position: officer.Position,
+		Unit:     officer.Unit,
+		Subjects: subjects,
+	}
+	for _, subject := range subjects {
+		view.Score += subject.Score
+	}
+	return view
+}
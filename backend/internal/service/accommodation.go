@@ -0,0 +1,172 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/lehaisonagentai3/free-contest/backend/internal/model"
+	"github.com/lehaisonagentai3/free-contest/backend/internal/utils"
+)
+
+// loadAccommodations reads the accommodations file of the config, skipping
+// entries of unknown officers or subjects
+func (s *ContestService) loadAccommodations() error {
+	s.accommodations = make(map[int]map[int]*model.Accommodation)
+	if s.conf.AccommodationPath == "" {
+		return nil
+	}
+	accommodations, err := utils.LoadAccommodations(s.conf.AccommodationPath)
+	if err != nil {
+		return err
+	}
+	for _, accommodation := range accommodations {
+		if err := s.validateAccommodation(accommodation); err != nil {
+			fmt.Printf("Skipping accommodation of officer %d in subject %d: %v\n", accommodation.OfficerID, accommodation.SubjectID, err)
+			continue
+		}
+		s.putAccommodation(accommodation)
+	}
+	if len(accommodations) > 0 {
+		fmt.Printf("Loaded %d accommodations from %s\n", len(accommodations), s.conf.AccommodationPath)
+	}
+	return nil
+}
+
+func (s *ContestService) validateAccommodation(accommodation *model.Accommodation) error {
+	if _, ok := s.mapOfficers[accommodation.OfficerID]; !ok {
+		return ErrOfficerNotFound
+	}
+	if _, ok := s.mapSubjects[accommodation.SubjectID]; accommodation.SubjectID != 0 && !ok {
+		return ErrSubjectNotFound
+	}
+	if accommodation.ExtraMinutes < 0 || accommodation.ExtraPercent < 0 {
+		return fmt.Errorf("extra time cannot be negative")
+	}
+	return nil
+}
+
+// putAccommodation records an accommodation. The caller holds
+// accommodationMu or is restoring the state.
+func (s *ContestService) putAccommodation(accommodation *model.Accommodation) {
+	if _, ok := s.accommodations[accommodation.OfficerID]; !ok {
+		s.accommodations[accommodation.OfficerID] = make(map[int]*model.Accommodation)
+	}
+	s.accommodations[accommodation.OfficerID][accommodation.SubjectID] = accommodation
+}
+
+// accommodationFor returns the accommodation of an officer in a subject: the
+// one for that subject if any, otherwise the one for every subject
+func (s *ContestService) accommodationFor(officerID, subjectID int) *model.Accommodation {
+	s.accommodationMu.Lock()
+	defer s.accommodationMu.Unlock()
+	if accommodation, ok := s.accommodations[officerID][subjectID]; ok {
+		return accommodation
+	}
+	return s.accommodations[officerID][0]
+}
+
+// sortedAccommodations returns every accommodation by officer then subject.
+// The caller holds accommodationMu.
+func (s *ContestService) sortedAccommodations() []*model.Accommodation {
+	var accommodations []*model.Accommodation
+	for _, bySubject := range s.accommodations {
+		for _, accommodation := range bySubject {
+			accommodations = append(accommodations, accommodation)
+		}
+	}
+	sort.Slice(accommodations, func(i, j int) bool {
+		if accommodations[i].OfficerID != accommodations[j].OfficerID {
+			return accommodations[i].OfficerID < accommodations[j].OfficerID
+		}
+		return accommodations[i].SubjectID < accommodations[j].SubjectID
+	})
+	return accommodations
+}
+
+// ListAccommodations returns every accommodation by officer then subject
+func (s *ContestService) ListAccommodations() []*model.Accommodation {
+	s.accommodationMu.Lock()
+	defer s.accommodationMu.Unlock()
+	return s.sortedAccommodations()
+}
+
+// SetAccommodation creates or replaces the accommodation of an officer in a
+// subject, or in every subject when SubjectID is 0, and writes the
+// accommodations file. It applies to tests generated from now on; tests
+// already generated keep their duration and can be extended instead.
+func (s *ContestService) SetAccommodation(accommodation *model.Accommodation, admin string) (*model.Accommodation, error) {
+	if err := s.validateAccommodation(accommodation); err != nil {
+		return nil, err
+	}
+	accommodation = &model.Accommodation{
+		OfficerID:    accommodation.OfficerID,
+		SubjectID:    accommodation.SubjectID,
+		ExtraMinutes: accommodation.ExtraMinutes,
+		ExtraPercent: accommodation.ExtraPercent,
+		Note:         accommodation.Note,
+	}
+
+	s.accommodationMu.Lock()
+	defer s.accommodationMu.Unlock()
+	previous, existed := s.accommodations[accommodation.OfficerID][accommodation.SubjectID]
+	s.putAccommodation(accommodation)
+	detail := fmt.Sprintf("+%d minutes, +%d%% in subject %d", accommodation.ExtraMinutes, accommodation.ExtraPercent, accommodation.SubjectID)
+	if accommodation.SubjectID == 0 {
+		detail = fmt.Sprintf("+%d minutes, +%d%% in every subject", accommodation.ExtraMinutes, accommodation.ExtraPercent)
+	}
+	if err := s.saveAccommodations(accommodation.OfficerID, detail, accommodation.Note, admin); err != nil {
+		if existed {
+			s.accommodations[accommodation.OfficerID][accommodation.SubjectID] = previous
+		} else {
+			delete(s.accommodations[accommodation.OfficerID], accommodation.SubjectID)
+		}
+		return nil, err
+	}
+	return accommodation, nil
+}
+
+// DeleteAccommodation removes the accommodation of an officer in a subject,
+// or the one for every subject when subjectID is 0
+func (s *ContestService) DeleteAccommodation(officerID, subjectID int, admin string) error {
+	s.accommodationMu.Lock()
+	defer s.accommodationMu.Unlock()
+	previous, ok := s.accommodations[officerID][subjectID]
+	if !ok {
+		return ErrAccommodationNotFound
+	}
+	delete(s.accommodations[officerID], subjectID)
+	detail := fmt.Sprintf("removed in subject %d", subjectID)
+	if subjectID == 0 {
+		detail = "removed in every subject"
+	}
+	if err := s.saveAccommodations(officerID, detail, "", admin); err != nil {
+		s.accommodations[officerID][subjectID] = previous
+		return err
+	}
+	return nil
+}
+
+// saveAccommodations writes the accommodations file and records the change in
+// the audit trail. The caller holds accommodationMu.
+func (s *ContestService) saveAccommodations(officerID int, detail, reason, admin string) error {
+	if s.conf.AccommodationPath != "" {
+		if err := utils.SaveAccommodations(s.conf.AccommodationPath, s.sortedAccommodations()); err != nil {
+			return fmt.Errorf("failed to save accommodations: %w", err)
+		}
+	}
+	action := &model.AdminAction{
+		ID:        utils.NewID(),
+		Action:    model.AdminActionAccommodate,
+		Admin:     admin,
+		OfficerID: officerID,
+		Reason:    reason,
+		Detail:    detail,
+		At:        time.Now().Unix(),
+	}
+	if err := s.store.SaveAdminAction(action, nil, nil); err != nil {
+		// The file is already written, the change stands
+		fmt.Printf("Failed to record accommodation of officer %d in the audit trail: %v\n", officerID, err)
+	}
+	return nil
+}
@@ -0,0 +1,405 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/lehaisonagentai3/free-contest/backend/internal/config"
+	"github.com/lehaisonagentai3/free-contest/backend/internal/model"
+	"github.com/lehaisonagentai3/free-contest/backend/internal/utils"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// dummyPasswordHash is compared against when the username is unknown, so
+// unknown and known usernames take the same time to reject
+var dummyPasswordHash, _ = bcrypt.GenerateFromPassword([]byte("free-contest"), bcrypt.DefaultCost)
+
+// AuthenticateAdmin checks an administrator's password against the bcrypt
+// hash in the config and returns the username
+func (s *ContestService) AuthenticateAdmin(username, password string) (string, error) {
+	var account *config.AdminAccount
+	for _, candidate := range s.conf.Admins {
+		if candidate.Username == username {
+			account = candidate
+			break
+		}
+	}
+	hash := dummyPasswordHash
+	if account != nil {
+		hash = []byte(account.PasswordHash)
+	}
+	if err := bcrypt.CompareHashAndPassword(hash, []byte(password)); err != nil || account == nil || password == "" {
+		return "", ErrInvalidCredentials
+	}
+	return account.Username, nil
+}
+
+// ListInProgressTests returns the progress of every started test that is not
+// finished yet, earliest start first
+func (s *ContestService) ListInProgressTests() []*model.TestProgress {
+	s.mu.RLock()
+	candidates := make([]*model.Test, 0, len(s.mapTests))
+	for _, test := range s.mapTests {
+		candidates = append(candidates, test)
+	}
+	s.mu.RUnlock()
+
+	now := time.Now().Unix()
+	var tests []*model.TestProgress
+	for _, test := range candidates {
+		officer, unlock, err := s.lockOfficer(test.Officer.ID)
+		if err != nil {
+			continue
+		}
+		if test.StartTime > 0 && !test.IsFinished {
+			answered := 0
+			for _, saved := range test.SavedAnswers {
+				if saved.Answer != "" {
+					answered++
+				}
+			}
+			tests = append(tests, &model.TestProgress{
+				TestID:        test.ID,
+				OfficerID:     officer.ID,
+				OfficerName:   officer.Name,
+				SubjectID:     test.Subject.ID,
+				SubjectName:   test.Subject.Name,
+				Attempt:       test.Attempt,
+				StartTime:     test.StartTime,
+				Deadline:      test.Deadline(),
+				RemainingTime: test.Remaining(now),
+				NumQuestions:  len(test.Questions),
+				NumAnswered:   answered,
+				PausedAt:      test.PausedAt,
+			})
+		}
+		unlock()
+	}
+	sort.Slice(tests, func(i, j int) bool {
+		return tests[i].StartTime < tests[j].StartTime
+	})
+	return tests
+}
+
+// GetOfficerSubmissions returns every submission of an officer, oldest first
+func (s *ContestService) GetOfficerSubmissions(officerID int) ([]*model.Submission, error) {
+	officer, unlock, err := s.lockOfficer(officerID)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+	return append([]*model.Submission{}, officer.ListSubmission...), nil
+}
+
+// lockTest finds a test by ID and takes the lock of its officer, returning
+// the test and officer with the function releasing the lock
+func (s *ContestService) lockTest(testID string) (*model.Test, *model.Officer, func(), error) {
+	s.mu.RLock()
+	test, ok := s.mapTests[testID]
+	s.mu.RUnlock()
+	if !ok {
+		return nil, nil, nil, ErrTestNotFound
+	}
+	officer, unlock, err := s.lockOfficer(test.Officer.ID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	// The test may have been reset while waiting for the lock
+	if test.ResetAt > 0 {
+		unlock()
+		return nil, nil, nil, ErrTestNotFound
+	}
+	return test, officer, unlock, nil
+}
+
+// newAdminAction returns the audit trail entry of an action on a test
+func newAdminAction(action, admin, reason string, test *model.Test, now int64) *model.AdminAction {
+	return &model.AdminAction{
+		ID:        utils.NewID(),
+		Action:    action,
+		Admin:     admin,
+		OfficerID: test.Officer.ID,
+		TestID:    test.ID,
+		Reason:    reason,
+		At:        now,
+	}
+}
+
+// InvalidateTest closes a test for good: it can no longer be started or
+// answered, and its submission, if any, no longer counts toward the score.
+// The test still counts as an attempt.
+func (s *ContestService) InvalidateTest(testID, reason, admin string) (*model.Test, error) {
+	test, officer, unlock, err := s.lockTest(testID)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	if test.InvalidatedAt > 0 {
+		return nil, ErrTestInvalidated
+	}
+	previous := *test
+	now := time.Now().Unix()
+	test.InvalidatedAt = now
+	test.InvalidatedBy = admin
+	test.InvalidReason = reason
+	if !test.IsFinished {
+		test.IsFinished = true
+		test.FinishedAt = now
+	}
+	action := newAdminAction(model.AdminActionInvalidate, admin, reason, test, now)
+
+	// Submissions are shared with copies handed out earlier, replace rather
+	// than modify
+	index := -1
+	for i, submission := range officer.ListSubmission {
+		if submission.TestID == test.ID {
+			index = i
+		}
+	}
+	if index < 0 {
+		err = s.store.SaveAdminAction(action, test, nil)
+	} else {
+		invalidated := *officer.ListSubmission[index]
+		invalidated.Invalidated = true
+		action.SubmissionID = invalidated.ID
+		if err = s.store.SaveAdminAction(action, test, &invalidated); err == nil {
+			submissions := append([]*model.Submission{}, officer.ListSubmission...)
+			submissions[index] = &invalidated
+			officer.ListSubmission = submissions
+		}
+	}
+	if err != nil {
+		*test = previous
+		return nil, fmt.Errorf("failed to save test: %w", err)
+	}
+	return copyTest(test), nil
+}
+
+// ExtendTest adds minutes to the duration of a test that is not submitted
+// yet, for instance to give back the time lost to a crash
+func (s *ContestService) ExtendTest(testID string, minutes int, reason, admin string) (*model.Test, error) {
+	test, _, unlock, err := s.lockTest(testID)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	if test.InvalidatedAt > 0 {
+		return nil, ErrTestInvalidated
+	}
+	if test.IsFinished {
+		return nil, ErrTestAlreadySubmitted
+	}
+	now := time.Now().Unix()
+	action := newAdminAction(model.AdminActionExtend, admin, reason, test, now)
+	action.Detail = fmt.Sprintf("+%d minutes", minutes)
+	test.Duration += minutes * 60
+	if err := s.store.SaveAdminAction(action, test, nil); err != nil {
+		test.Duration -= minutes * 60
+		return nil, fmt.Errorf("failed to save test: %w", err)
+	}
+	return copyTest(test), nil
+}
+
+// PauseTest stops the timer of a running test. While paused the officer can
+// neither answer nor submit, and the test is not auto-submitted.
+func (s *ContestService) PauseTest(testID, reason, admin string) (*model.Test, error) {
+	test, _, unlock, err := s.lockTest(testID)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	if test.InvalidatedAt > 0 {
+		return nil, ErrTestInvalidated
+	}
+	if test.StartTime == 0 {
+		return nil, ErrTestNotStarted
+	}
+	if test.IsFinished {
+		return nil, ErrTestAlreadySubmitted
+	}
+	if test.PausedAt > 0 {
+		return nil, ErrTestPaused
+	}
+	now := time.Now().Unix()
+	if now > test.Deadline() {
+		return nil, ErrTestExpired
+	}
+	action := newAdminAction(model.AdminActionPause, admin, reason, test, now)
+	action.Detail = fmt.Sprintf("%d seconds left", test.Remaining(now))
+	test.PausedAt = now
+	if err := s.store.SaveAdminAction(action, test, nil); err != nil {
+		test.PausedAt = 0
+		return nil, fmt.Errorf("failed to save test: %w", err)
+	}
+	return copyTest(test), nil
+}
+
+// ResumeTest restarts the timer of a paused test, pushing the deadline back
+// by the time spent paused
+func (s *ContestService) ResumeTest(testID, reason, admin string) (*model.Test, error) {
+	test, _, unlock, err := s.lockTest(testID)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	if test.PausedAt == 0 || test.IsFinished {
+		return nil, ErrTestNotPaused
+	}
+	now := time.Now().Unix()
+	paused := int(now - test.PausedAt)
+	action := newAdminAction(model.AdminActionResume, admin, reason, test, now)
+	action.Detail = fmt.Sprintf("paused for %d seconds", paused)
+	pausedAt := test.PausedAt
+	test.PausedAt = 0
+	test.PausedFor += paused
+	if err := s.store.SaveAdminAction(action, test, nil); err != nil {
+		test.PausedAt = pausedAt
+		test.PausedFor -= paused
+		return nil, fmt.Errorf("failed to save test: %w", err)
+	}
+	return copyTest(test), nil
+}
+
+// ResetTest discards a test that is not submitted yet, started or not, or
+// that was auto-submitted, for instance after the officer's PC stayed down
+// past the deadline. The auto-submission is voided. The test no longer counts
+// as an attempt, so the officer's next request for the subject generates a
+// fresh paper. The discarded test is kept on disk for the record.
+func (s *ContestService) ResetTest(testID, reason, admin string) (*model.Test, error) {
+	test, officer, unlock, err := s.lockTest(testID)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	if test.InvalidatedAt > 0 {
+		return nil, ErrTestInvalidated
+	}
+	index := -1
+	if test.IsFinished {
+		index = slices.IndexFunc(officer.ListSubmission, func(submission *model.Submission) bool {
+			return submission.TestID == test.ID && submission.Status == model.SubmissionStatusAuto
+		})
+		if index < 0 {
+			return nil, ErrTestAlreadySubmitted
+		}
+	}
+	now := time.Now().Unix()
+	action := newAdminAction(model.AdminActionReset, admin, reason, test, now)
+	action.Detail = fmt.Sprintf("attempt %d", test.Attempt)
+	var voided *model.Submission
+	if index >= 0 {
+		// Submissions are shared with copies handed out earlier, replace
+		// rather than modify
+		submission := *officer.ListSubmission[index]
+		if submission.VoidedAt == 0 {
+			submission.VoidedAt = now
+			submission.VoidedBy = admin
+			submission.VoidReason = reason
+		}
+		voided = &submission
+		action.SubmissionID = submission.ID
+		action.Detail += fmt.Sprintf(", auto-submitted score %.2f voided", submission.Score)
+	}
+	test.ResetAt = now
+	if err := s.store.SaveAdminAction(action, test, voided); err != nil {
+		test.ResetAt = 0
+		return nil, fmt.Errorf("failed to save test: %w", err)
+	}
+	if voided != nil {
+		submissions := append([]*model.Submission{}, officer.ListSubmission...)
+		submissions[index] = voided
+		officer.ListSubmission = submissions
+	}
+	s.removeTest(test)
+	return copyTest(test), nil
+}
+
+// VoidSubmission cancels the score of a submission. The test stays submitted
+// and still counts as an attempt.
+func (s *ContestService) VoidSubmission(submissionID, reason, admin string) (*model.Submission, error) {
+	for officerID := range s.mapOfficers {
+		officer, unlock, err := s.lockOfficer(officerID)
+		if err != nil {
+			return nil, err
+		}
+		submission, err := s.voidSubmission(officer, submissionID, reason, admin)
+		unlock()
+		if !errors.Is(err, ErrSubmissionNotFound) {
+			return submission, err
+		}
+	}
+	return nil, ErrSubmissionNotFound
+}
+
+// voidSubmission voids a submission of the officer. The caller holds the
+// officer lock.
+func (s *ContestService) voidSubmission(officer *model.Officer, submissionID, reason, admin string) (*model.Submission, error) {
+	index := slices.IndexFunc(officer.ListSubmission, func(submission *model.Submission) bool {
+		return submission.ID == submissionID
+	})
+	if index < 0 {
+		return nil, ErrSubmissionNotFound
+	}
+	if officer.ListSubmission[index].VoidedAt > 0 {
+		return nil, ErrSubmissionVoided
+	}
+
+	// Submissions are shared with copies handed out earlier, replace rather
+	// than modify
+	now := time.Now().Unix()
+	voided := *officer.ListSubmission[index]
+	voided.VoidedAt = now
+	voided.VoidedBy = admin
+	voided.VoidReason = reason
+	action := &model.AdminAction{
+		ID:           utils.NewID(),
+		Action:       model.AdminActionVoid,
+		Admin:        admin,
+		OfficerID:    officer.ID,
+		TestID:       voided.TestID,
+		SubmissionID: voided.ID,
+		Reason:       reason,
+		Detail:       fmt.Sprintf("score %.2f", voided.Score),
+		At:           now,
+	}
+	if err := s.store.SaveAdminAction(action, nil, &voided); err != nil {
+		return nil, fmt.Errorf("failed to save submission: %w", err)
+	}
+	submissions := append([]*model.Submission{}, officer.ListSubmission...)
+	submissions[index] = &voided
+	officer.ListSubmission = submissions
+	return &voided, nil
+}
+
+// ListAdminActions returns the audit trail, oldest first, optionally limited
+// to an officer (officerID > 0) and to a test (testID not empty)
+func (s *ContestService) ListAdminActions(officerID int, testID string) ([]*model.AdminAction, error) {
+	actions, err := s.store.LoadAdminActions()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load audit trail: %w", err)
+	}
+	filtered := make([]*model.AdminAction, 0, len(actions))
+	for _, action := range actions {
+		if (officerID > 0 && action.OfficerID != officerID) || (testID != "" && action.TestID != testID) {
+			continue
+		}
+		filtered = append(filtered, action)
+	}
+	return filtered, nil
+}
+
+// ValidateQuestionBank checks the contest folder as it is on disk now, for
+// instance after the question authors edited it. The loaded bank is not
+// changed until the next restart.
+func (s *ContestService) ValidateQuestionBank() *utils.ValidationReport {
+	_, report := utils.ValidateContest(s.conf.ContestPath)
+	return report
+}
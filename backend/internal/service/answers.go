@@ -0,0 +1,100 @@
+package service
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/lehaisonagentai3/free-contest/backend/internal/model"
+)
+
+// Reasons an answer is rejected
+const (
+	AnswerErrorUnknownQuestion = "unknown_question" // the key is not a question of the test
+	AnswerErrorInvalidOption   = "invalid_option"   // the letter is not an option shown for the question
+)
+
+// AnswerError describes one rejected entry of a submitted answer map
+type AnswerError struct {
+	QuestionID string `json:"question_id"`
+	Answer     string `json:"answer"`
+	Reason     string `json:"reason"`
+	Message    string `json:"message"`
+}
+
+// AnswerValidationError is returned when submitted answers do not fit the test
+type AnswerValidationError struct {
+	Errors []AnswerError `json:"errors"`
+}
+
+func (e *AnswerValidationError) Error() string {
+	return fmt.Sprintf("invalid answers: %d rejected", len(e.Errors))
+}
+
+// normalizeAnswers checks the answers against the test's questions and
+// returns one entry per question: the displayed letter in upper case, or ""
+// for a question left unanswered. Nil answers leave every question unanswered.
+func normalizeAnswers(test *model.Test, answers map[string]string) (map[string]string, error) {
+	valid, err := validateAnswers(test, answers)
+	if err != nil {
+		return nil, err
+	}
+	normalized := make(map[string]string, len(test.Questions))
+	for _, question := range test.Questions {
+		questionID := fmt.Sprint(question.ID)
+		normalized[questionID] = valid[questionID]
+	}
+	return normalized, nil
+}
+
+// validateAnswers checks the answers against the test's questions and
+// returns them with letters in upper case and blank answers as ""
+func validateAnswers(test *model.Test, answers map[string]string) (map[string]string, error) {
+	questions := make(map[string]*model.Question, len(test.Questions))
+	for _, question := range test.Questions {
+		questions[fmt.Sprint(question.ID)] = question
+	}
+	valid := make(map[string]string, len(answers))
+
+	var errs []AnswerError
+	for questionID, answer := range answers {
+		question, ok := questions[questionID]
+		if !ok {
+			errs = append(errs, AnswerError{
+				QuestionID: questionID,
+				Answer:     answer,
+				Reason:     AnswerErrorUnknownQuestion,
+				Message:    fmt.Sprintf("question %s is not part of this test", questionID),
+			})
+			continue
+		}
+		letter := strings.ToUpper(strings.TrimSpace(answer))
+		if letter != "" && !slices.Contains(model.OptionLetters, question.CanonicalLetter(letter)) {
+			errs = append(errs, AnswerError{
+				QuestionID: questionID,
+				Answer:     answer,
+				Reason:     AnswerErrorInvalidOption,
+				Message:    fmt.Sprintf("%q is not an option of question %s", answer, questionID),
+			})
+			continue
+		}
+		valid[questionID] = letter
+	}
+	if len(errs) > 0 {
+		sort.Slice(errs, func(i, j int) bool {
+			return errs[i].QuestionID < errs[j].QuestionID
+		})
+		return nil, &AnswerValidationError{Errors: errs}
+	}
+	return valid, nil
+}
+
+// savedAnswerLetters returns the answers saved on the test by question ID
+func savedAnswerLetters(test *model.Test) map[string]string {
+	letters := make(map[string]string, len(test.SavedAnswers))
+	for questionID, saved := range test.SavedAnswers {
+		letters[questionID] = saved.Answer
+	}
+	return letters
+}
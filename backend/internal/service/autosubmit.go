@@ -0,0 +1,68 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/lehaisonagentai3/free-contest/backend/internal/model"
+)
+
+// autoSubmitInterval is how often the scheduler looks for expired tests
+const autoSubmitInterval = 15 * time.Second
+
+// runAutoSubmit finalizes expired tests until stop is closed
+func (s *ContestService) runAutoSubmit(stop <-chan struct{}) {
+	ticker := time.NewTicker(autoSubmitInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			s.autoSubmitExpired(time.Now().Unix())
+		}
+	}
+}
+
+// autoSubmitExpired finalizes every started, unfinished test whose deadline
+// and grace period have passed, grading the answers saved so far. Paused
+// tests wait for their resume.
+func (s *ContestService) autoSubmitExpired(now int64) []*model.Submission {
+	s.mu.RLock()
+	candidates := make([]*model.Test, 0, len(s.mapTests))
+	for _, test := range s.mapTests {
+		candidates = append(candidates, test)
+	}
+	s.mu.RUnlock()
+
+	var submissions []*model.Submission
+	for _, test := range candidates {
+		if submission := s.autoSubmitTest(test.Officer.ID, test, now); submission != nil {
+			submissions = append(submissions, submission)
+		}
+	}
+	return submissions
+}
+
+func (s *ContestService) autoSubmitTest(officerID int, test *model.Test, now int64) *model.Submission {
+	_, unlock, err := s.lockOfficer(officerID)
+	if err != nil {
+		return nil
+	}
+	defer unlock()
+
+	// Paused tests are not running out, reset ones are no longer attempts
+	if test.StartTime == 0 || test.IsFinished || test.PausedAt > 0 || test.ResetAt > 0 {
+		return nil
+	}
+	if now <= test.Deadline()+int64(s.conf.GracePeriod) {
+		return nil
+	}
+	submission, err := s.finalizeTest(test, savedAnswerLetters(test), model.SubmissionStatusAuto, now, "", "")
+	if err != nil {
+		fmt.Printf("Failed to auto-submit test %s of officer %d: %v\n", test.ID, officerID, err)
+		return nil
+	}
+	fmt.Printf("Auto-submitted test %s of officer %d\n", test.ID, officerID)
+	return submission
+}
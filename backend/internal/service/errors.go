@@ -0,0 +1,26 @@
+package service
+
+import "errors"
+
+// Errors returned by ContestService. Callers compare with errors.Is; the
+// controller package maps them to HTTP responses.
+var (
+	ErrOfficerNotFound       = errors.New("officer not found")
+	ErrSubjectNotFound       = errors.New("subject not found")
+	ErrTestNotFound          = errors.New("test not found")
+	ErrTestExpired           = errors.New("test is expired")
+	ErrTestAlreadyStarted    = errors.New("test already started")
+	ErrTestNotStarted        = errors.New("test has not been started yet")
+	ErrTestAlreadySubmitted  = errors.New("test has already been submitted")
+	ErrRetakeNotAvailable    = errors.New("retake is not available yet")
+	ErrInvalidCredentials    = errors.New("invalid credentials")
+	ErrIdempotencyKeyReused  = errors.New("idempotency key already used for another request")
+	ErrTestInvalidated       = errors.New("test has been invalidated")
+	ErrTestPaused            = errors.New("test is paused")
+	ErrTestNotPaused         = errors.New("test is not paused")
+	ErrSubmissionNotFound    = errors.New("submission not found")
+	ErrSubmissionVoided      = errors.New("submission has already been voided")
+	ErrAccommodationNotFound = errors.New("accommodation not found")
+	ErrNotEnoughQuestions    = errors.New("not enough questions for a test")
+	ErrNoSeed                = errors.New("test has no recorded seed")
+)
@@ -0,0 +1,134 @@
+package service
+
+import (
+	crand "crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math/rand/v2"
+	"strings"
+
+	"github.com/lehaisonagentai3/free-contest/backend/internal/model"
+)
+
+// newSeed returns a fresh seed for one test generation
+func newSeed() uint64 {
+	var b [8]byte
+	if _, err := crand.Read(b[:]); err != nil {
+		panic(err) // crypto/rand never fails on supported platforms
+	}
+	return binary.LittleEndian.Uint64(b[:])
+}
+
+// testRand returns a random source owned by a single test generation, so
+// concurrent generations never share state
+func testRand(seed uint64) *rand.Rand {
+	return rand.New(rand.NewPCG(seed, seed^0x9e3779b97f4a7c15))
+}
+
+// bankVersion fingerprints everything in a subject that affects the paper
+// generated from a seed: chapter order, question counts and question content
+func bankVersion(subject *model.Subject) string {
+	h := sha256.New()
+	for _, chapter := range subject.Chapters {
+		fmt.Fprintf(h, "chapter\x00%d\x00%s\x00%d\n", chapter.ID, chapter.Name, chapter.NumQuestionTest)
+		for _, q := range chapter.Questions {
+			fmt.Fprintf(h, "%d\x00%s\x00%s\x00%s\x00%s\x00%s\x00%s\n", q.ID, q.Content, q.AnswerA, q.AnswerB, q.AnswerC, q.AnswerD, q.Correct)
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil))[:16]
+}
+
+// generatePaper returns the questions of the test generated from the seed.
+// The same seed and bank version always give the same paper.
+func generatePaper(subject *model.Subject, seed uint64, excluded []int) ([]*model.Question, error) {
+	return selectQuestions(subject, testRand(seed), excluded)
+}
+
+// GeneratePaper returns the paper of a first attempt generated from the
+// seed, exactly as the server would, so tools can preview a subject's test
+func GeneratePaper(subject *model.Subject, seed uint64) ([]*model.Question, error) {
+	return generatePaper(subject, seed, nil)
+}
+
+// samePaper reports whether two papers have the same questions with the same
+// options in the same order
+func samePaper(a, b []*model.Question) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].ID != b[i].ID || a[i].Content != b[i].Content ||
+			a[i].AnswerA != b[i].AnswerA || a[i].AnswerB != b[i].AnswerB ||
+			a[i].AnswerC != b[i].AnswerC || a[i].AnswerD != b[i].AnswerD ||
+			a[i].Correct != b[i].Correct ||
+			strings.Join(a[i].OptionOrder, "") != strings.Join(b[i].OptionOrder, "") {
+			return false
+		}
+	}
+	return true
+}
+
+// selectQuestions draws NumQuestionTest questions from every chapter of the
+// subject, preferring questions not in excluded. The question bank is shared
+// by every request and is only read: the chosen questions are copied into
+// the test.
+func selectQuestions(subject *model.Subject, rng *rand.Rand, excluded []int) ([]*model.Question, error) {
+	isExcluded := make(map[int]bool, len(excluded))
+	for _, id := range excluded {
+		isExcluded[id] = true
+	}
+	listQuestions := []*model.Question{}
+	for _, chapter := range subject.Chapters {
+		if chapter.NumQuestionTest <= 0 {
+			continue
+		}
+		if len(chapter.Questions) < chapter.NumQuestionTest {
+			return nil, fmt.Errorf("chapter %s: %w", chapter.Name, ErrNotEnoughQuestions)
+		}
+		// Unseen questions first, seen ones only to fill up the chapter
+		perm := rng.Perm(len(chapter.Questions))
+		picked := make([]int, 0, chapter.NumQuestionTest)
+		for _, wantSeen := range []bool{false, true} {
+			for _, index := range perm {
+				if len(picked) < chapter.NumQuestionTest && isExcluded[chapter.Questions[index].ID] == wantSeen {
+					picked = append(picked, index)
+				}
+			}
+		}
+		for _, index := range picked {
+			question := *chapter.Questions[index]
+			shuffleOptions(&question, rng)
+			listQuestions = append(listQuestions, &question)
+		}
+	}
+	return listQuestions, nil
+}
+
+// shuffleOptions reorders the non-empty options of a test question and records
+// the original letter shown at each position in OptionOrder
+func shuffleOptions(question *model.Question, rng *rand.Rand) {
+	options := map[string]string{
+		"A": question.AnswerA,
+		"B": question.AnswerB,
+		"C": question.AnswerC,
+		"D": question.AnswerD,
+	}
+	var letters []string
+	for _, letter := range model.OptionLetters {
+		if strings.TrimSpace(options[letter]) != "" {
+			letters = append(letters, letter)
+		}
+	}
+	rng.Shuffle(len(letters), func(i, j int) {
+		letters[i], letters[j] = letters[j], letters[i]
+	})
+
+	displayed := make([]string, len(model.OptionLetters))
+	for i, letter := range letters {
+		displayed[i] = options[letter]
+	}
+	question.AnswerA, question.AnswerB, question.AnswerC, question.AnswerD = displayed[0], displayed[1], displayed[2], displayed[3]
+	question.OptionOrder = letters
+}
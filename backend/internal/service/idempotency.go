@@ -0,0 +1,30 @@
+package service
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+
+	"github.com/lehaisonagentai3/free-contest/backend/internal/model"
+)
+
+// MaxIdempotencyKeyLength bounds the keys clients may send
+const MaxIdempotencyKeyLength = 128
+
+// payloadHash fingerprints validated answers so a retry can be told apart
+// from a different request reusing its key. Map keys are marshalled sorted.
+func payloadHash(answers map[string]string) string {
+	data, _ := json.Marshal(answers)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// submissionOf returns the submission recorded for the test, if any
+func submissionOf(test *model.Test) *model.Submission {
+	for _, submission := range test.Officer.ListSubmission {
+		if submission.TestID == test.ID {
+			return submission
+		}
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+package service
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/lehaisonagentai3/free-contest/backend/internal/model"
+	"github.com/lehaisonagentai3/free-contest/backend/internal/utils"
+)
+
+// migrationStableQuestionIDs moves data files written while questions were
+// numbered in folder order to the stable IDs of the bank
+const migrationStableQuestionIDs = "stable_question_ids"
+
+// migrateQuestionIDs rewrites, once per data file, the question IDs of the
+// stored tests and the question keys of their answers to the stable IDs of
+// the bank. Questions are matched by subject and text. Questions no longer
+// in the bank get a negative ID derived from the old one, which no bank
+// question has, so their answers still resolve within the test.
+func (s *ContestService) migrateQuestionIDs(tests []*model.Test, submissions []*model.Submission) error {
+	done, err := s.store.Migrated(migrationStableQuestionIDs)
+	if err != nil || done {
+		return err
+	}
+
+	// Bank IDs by subject and question text, identical questions in bank order
+	bank := make(map[int]map[string][]int)
+	for _, subject := range s.contest.Subjects {
+		byText := make(map[string][]int)
+		for _, chapter := range subject.Chapters {
+			for _, question := range chapter.Questions {
+				key := utils.QuestionKey(question.Content)
+				byText[key] = append(byText[key], question.ID)
+			}
+		}
+		bank[subject.ID] = byText
+	}
+
+	// The old IDs numbered the whole bank, so an officer's earlier attempts,
+	// which ExcludedQuestionIDs refer to, map them the same way
+	type attemptsKey struct{ officerID, subjectID int }
+	attemptMappings := make(map[attemptsKey]map[int]int)
+	testMappings := make(map[string]map[int]int)
+	for _, test := range tests {
+		if test.Subject == nil || test.Officer == nil {
+			continue
+		}
+		key := attemptsKey{test.Officer.ID, test.Subject.ID}
+		if attemptMappings[key] == nil {
+			attemptMappings[key] = make(map[int]int)
+		}
+		mapping := make(map[int]int, len(test.Questions))
+		used := make(map[int]bool, len(test.Questions))
+		for _, question := range test.Questions {
+			newID := legacyQuestionID(question.ID)
+			for _, id := range bank[test.Subject.ID][utils.QuestionKey(question.Content)] {
+				if !used[id] {
+					newID = id
+					break
+				}
+			}
+			used[newID] = true
+			mapping[question.ID] = newID
+			if _, ok := attemptMappings[key][question.ID]; !ok {
+				attemptMappings[key][question.ID] = newID
+			}
+			question.ID = newID
+		}
+		test.SavedAnswers = remapAnswerKeys(test.SavedAnswers, mapping)
+		testMappings[test.ID] = mapping
+	}
+	for _, test := range tests {
+		if test.Subject == nil || test.Officer == nil {
+			continue
+		}
+		mapping := attemptMappings[attemptsKey{test.Officer.ID, test.Subject.ID}]
+		for i, id := range test.ExcludedQuestionIDs {
+			if newID, ok := mapping[id]; ok {
+				test.ExcludedQuestionIDs[i] = newID
+			} else {
+				test.ExcludedQuestionIDs[i] = legacyQuestionID(id)
+			}
+		}
+	}
+	for _, submission := range submissions {
+		mapping := testMappings[submission.TestID]
+		submission.Answers = remapAnswerKeys(submission.Answers, mapping)
+		submission.CanonicalAnswers = remapAnswerKeys(submission.CanonicalAnswers, mapping)
+	}
+
+	if err := s.store.SaveMigration(migrationStableQuestionIDs, time.Now().Unix(), tests, submissions); err != nil {
+		return err
+	}
+	if len(tests) > 0 {
+		fmt.Printf("Moved %d stored tests and %d submissions to stable question IDs\n", len(tests), len(submissions))
+	}
+	return nil
+}
+
+// legacyQuestionID is the ID kept by a question of a stored test that is no
+// longer in the bank: negative, and never 0 which is no ID at all
+func legacyQuestionID(id int) int {
+	return -id - 1
+}
+
+// remapAnswerKeys returns the answers keyed by the new question IDs. Keys
+// that are not known question IDs are kept as they are.
+func remapAnswerKeys[V any](answers map[string]V, mapping map[int]int) map[string]V {
+	if answers == nil {
+		return nil
+	}
+	remapped := make(map[string]V, len(answers))
+	for key, value := range answers {
+		if id, err := strconv.Atoi(key); err == nil {
+			if newID, ok := mapping[id]; ok {
+				key = strconv.Itoa(newID)
+			}
+		}
+		remapped[key] = value
+	}
+	return remapped
+}
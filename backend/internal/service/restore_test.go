@@ -0,0 +1,209 @@
+package service
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/lehaisonagentai3/free-contest/backend/internal/config"
+	"github.com/xuri/excelize/v2"
+)
+
+// restoreConfig writes a contest of one subject with six questions and a
+// roster of two officers to a temporary folder, and returns a config over
+// them whose data file lives in the same folder
+func restoreConfig(t *testing.T) *config.AppConfig {
+	t.Helper()
+	root := t.TempDir()
+
+	var questions [][]any
+	for i := 1; i <= 6; i++ {
+		questions = append(questions,
+			[]any{fmt.Sprintf("Câu %d", i), fmt.Sprintf("Câu hỏi %d", i)},
+			[]any{"A", "Một"}, []any{"B", "Hai"}, []any{"C", "Ba"}, []any{"D", "Bốn"},
+			[]any{"Đáp án", string(rune('A' + i%4))},
+			[]any{},
+		)
+	}
+	writeRestoreSheet(t, filepath.Join(root, "contest", "Điều lệnh - 30 - phút", "Chương 1 - 4 - câu", "cau_hoi.xlsx"), questions)
+	writeRestoreSheet(t, filepath.Join(root, "officers.xlsx"), [][]any{
+		{1, "Nguyễn Văn A", "Đại úy", "Đại đội trưởng", "Đại đội 1", "1234"},
+		{2, "Trần Văn B", "Thượng úy", "Trung đội trưởng", "Đại đội 1", "5678"},
+	})
+	return &config.AppConfig{
+		ContestPath: filepath.Join(root, "contest"),
+		OfficerPath: filepath.Join(root, "officers.xlsx"),
+		DataPath:    filepath.Join(root, "contest.db"),
+	}
+}
+
+// writeRestoreSheet writes the rows to the first sheet of a new workbook
+func writeRestoreSheet(t *testing.T, path string, rows [][]any) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	f := excelize.NewFile()
+	defer f.Close()
+	for i, row := range rows {
+		cell, _ := excelize.CoordinatesToCellName(1, i+1)
+		f.SetSheetRow("Sheet1", cell, &row)
+	}
+	if err := f.SaveAs(path); err != nil {
+		t.Fatalf("Failed to write workbook: %v", err)
+	}
+}
+
+func TestStateSurvivesRestart(t *testing.T) {
+	conf := restoreConfig(t)
+	s, err := NewContestService(conf)
+	if err != nil {
+		t.Fatalf("Failed to create service: %v", err)
+	}
+
+	// Officer 1 is halfway through with an answer saved, officer 2 has
+	// submitted
+	inProgress, err := s.GetSubjectTestForOfficer(1, 1)
+	if err != nil {
+		t.Fatalf("Failed to get test: %v", err)
+	}
+	started, err := s.StartTest(1, inProgress.ID)
+	if err != nil {
+		t.Fatalf("Failed to start test: %v", err)
+	}
+	savedQuestion := fmt.Sprint(inProgress.Questions[0].ID)
+	if _, err := s.SaveAnswers(1, inProgress.ID, map[string]string{savedQuestion: "C"}, ""); err != nil {
+		t.Fatalf("Failed to save answer: %v", err)
+	}
+	finished, err := s.GetSubjectTestForOfficer(2, 1)
+	if err != nil {
+		t.Fatalf("Failed to get test: %v", err)
+	}
+	if _, err := s.StartTest(2, finished.ID); err != nil {
+		t.Fatalf("Failed to start test: %v", err)
+	}
+	answers := make(map[string]string)
+	for _, question := range finished.Questions {
+		answers[fmt.Sprint(question.ID)] = question.DisplayedLetter(question.Correct)
+	}
+	submission, err := s.SubmitTest(2, finished.ID, answers, "")
+	if err != nil {
+		t.Fatalf("Failed to submit test: %v", err)
+	}
+	if err := s.Close(); err != nil {
+		t.Fatalf("Failed to close service: %v", err)
+	}
+
+	s, err = NewContestService(conf)
+	if err != nil {
+		t.Fatalf("Failed to reopen service: %v", err)
+	}
+	defer s.Close()
+
+	resumed, err := s.GetSubjectTestForOfficer(1, 1)
+	if err != nil {
+		t.Fatalf("Failed to get test after restart: %v", err)
+	}
+	if resumed.ID != inProgress.ID || resumed.StartTime != started.StartTime || resumed.IsFinished {
+		t.Errorf("expected the started test back, got ID %v started at %d finished %v", resumed.ID, resumed.StartTime, resumed.IsFinished)
+	}
+	// The clock kept running by the start time, not by the restart
+	if left := resumed.RemainingTime; left > started.RemainingTime || left < started.RemainingTime-5 {
+		t.Errorf("expected about %d seconds left, got %d", started.RemainingTime, left)
+	}
+	if saved := resumed.SavedAnswers[savedQuestion]; saved.Answer != "C" || len(resumed.SavedAnswers) != 1 {
+		t.Errorf("expected the saved answer back, got %+v", resumed.SavedAnswers)
+	}
+	if len(resumed.Questions) != len(inProgress.Questions) {
+		t.Fatalf("expected %d questions, got %d", len(inProgress.Questions), len(resumed.Questions))
+	}
+	for i, question := range resumed.Questions {
+		if question.ID != inProgress.Questions[i].ID || question.Correct != inProgress.Questions[i].Correct {
+			t.Errorf("question %d changed: %+v", i, question)
+		}
+	}
+
+	done, err := s.GetSubjectTestForOfficer(2, 1)
+	if err != nil {
+		t.Fatalf("Failed to get finished test after restart: %v", err)
+	}
+	if done.ID != finished.ID || !done.IsFinished {
+		t.Errorf("expected the finished test back, got ID %v finished %v", done.ID, done.IsFinished)
+	}
+	officer, err := s.GetOfficerByID(2)
+	if err != nil {
+		t.Fatalf("Failed to get officer: %v", err)
+	}
+	if len(officer.ListSubmission) != 1 || officer.ListSubmission[0].Score != submission.Score || submission.Score != 10 {
+		t.Errorf("expected the submission scoring %v back, got %+v", submission.Score, officer.ListSubmission)
+	}
+}
+
+func TestRestoreFollowsSubjectsByName(t *testing.T) {
+	conf := restoreConfig(t)
+	s, err := NewContestService(conf)
+	if err != nil {
+		t.Fatalf("Failed to create service: %v", err)
+	}
+	test, err := s.GetSubjectTestForOfficer(1, 1)
+	if err != nil {
+		t.Fatalf("Failed to get test: %v", err)
+	}
+	if _, err := s.StartTest(1, test.ID); err != nil {
+		t.Fatalf("Failed to start test: %v", err)
+	}
+	if _, err := s.SubmitTest(1, test.ID, map[string]string{fmt.Sprint(test.Questions[0].ID): "A"}, ""); err != nil {
+		t.Fatalf("Failed to submit test: %v", err)
+	}
+	if err := s.Close(); err != nil {
+		t.Fatalf("Failed to close service: %v", err)
+	}
+
+	// A subject folder sorting first and a stray file move "Điều lệnh" to ID 2
+	questions, err := os.ReadFile(filepath.Join(conf.ContestPath, "Điều lệnh - 30 - phút", "Chương 1 - 4 - câu", "cau_hoi.xlsx"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	added := filepath.Join(conf.ContestPath, "Chính trị - 30 - phút", "Chương 1 - 4 - câu")
+	if err := os.MkdirAll(added, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(added, "cau_hoi.xlsx"), questions, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(conf.ContestPath, "0_ghi_chu.txt"), []byte("ghi chú"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err = NewContestService(conf)
+	if err != nil {
+		t.Fatalf("Failed to reopen service: %v", err)
+	}
+	defer s.Close()
+
+	if subject := s.mapSubjects[2]; subject == nil || subject.Name != test.Subject.Name {
+		t.Fatalf("expected subject 2 to be %q, got %+v", test.Subject.Name, subject)
+	}
+	done, err := s.GetSubjectTestForOfficer(1, 2)
+	if err != nil {
+		t.Fatalf("Failed to get test after restart: %v", err)
+	}
+	if done.ID != test.ID || !done.IsFinished {
+		t.Errorf("expected the finished test under subject 2, got ID %v finished %v", done.ID, done.IsFinished)
+	}
+	fresh, err := s.GetSubjectTestForOfficer(1, 1)
+	if err != nil {
+		t.Fatalf("Failed to get test of the added subject: %v", err)
+	}
+	if fresh.ID == test.ID || fresh.IsFinished {
+		t.Errorf("expected a new test for the added subject, got ID %v finished %v", fresh.ID, fresh.IsFinished)
+	}
+	officer, err := s.GetOfficerByID(1)
+	if err != nil {
+		t.Fatalf("Failed to get officer: %v", err)
+	}
+	if len(officer.ListSubmission) != 1 || officer.ListSubmission[0].SubjectID != 2 {
+		t.Errorf("expected the submission under subject 2, got %+v", officer.ListSubmission)
+	}
+}
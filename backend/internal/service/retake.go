@@ -0,0 +1,47 @@
+package service
+
+import (
+	"github.com/lehaisonagentai3/free-contest/backend/internal/config"
+	"github.com/lehaisonagentai3/free-contest/backend/internal/model"
+)
+
+// seenQuestionIDs returns the IDs of every question asked in the attempts
+func seenQuestionIDs(attempts []*model.Test) []int {
+	var ids []int
+	seen := make(map[int]bool)
+	for _, test := range attempts {
+		for _, question := range test.Questions {
+			if !seen[question.ID] {
+				seen[question.ID] = true
+				ids = append(ids, question.ID)
+			}
+		}
+	}
+	return ids
+}
+
+// subjectScore is the score an officer keeps in a subject. Submissions are in
+// the order they were made.
+func subjectScore(scoring string, submissions []*model.Submission) float32 {
+	if len(submissions) == 0 {
+		return 0
+	}
+	switch scoring {
+	case config.ScoringLast:
+		return submissions[len(submissions)-1].Score
+	case config.ScoringAverage:
+		var sum float32
+		for _, submission := range submissions {
+			sum += submission.Score
+		}
+		return sum / float32(len(submissions))
+	default:
+		best := submissions[0].Score
+		for _, submission := range submissions[1:] {
+			if submission.Score > best {
+				best = submission.Score
+			}
+		}
+		return best
+	}
+}
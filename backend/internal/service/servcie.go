@@ -1,23 +1,43 @@
 package service
 
 import (
+	"crypto/subtle"
 	"fmt"
-	"math/rand"
+	"maps"
+	"os"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/lehaisonagentai3/free-contest/backend/internal/config"
 	"github.com/lehaisonagentai3/free-contest/backend/internal/model"
+	"github.com/lehaisonagentai3/free-contest/backend/internal/store"
 	"github.com/lehaisonagentai3/free-contest/backend/internal/utils"
 )
 
+// ContestService is safe for concurrent use. Everything about one officer
+// (their tests and submissions) is changed only while holding that officer's
+// lock, and mu guards the maps shared by all officers. When both are needed
+// the officer lock is taken first. The subjects and questions loaded at
+// startup are never modified.
 type ContestService struct {
-	conf                    *config.AppConfig
-	mapUnits                map[string]string
-	mapSubjects             map[int]*model.Subject
-	mapOfficers             map[int]*model.Officer
-	contest                 *model.Contest              // Contest info loaded from config, include all subjecs and chapters and questions
-	mapOfficerToSubjectTest map[int]map[int]*model.Test // map[officerID][subjectID]Test
+	conf                     *config.AppConfig
+	mapUnits                 map[string]string
+	mapSubjects              map[int]*model.Subject
+	bankVersions             map[int]string // map[subjectID]bank fingerprint
+	mapOfficers              map[int]*model.Officer
+	officerLocks             map[int]*sync.Mutex           // map[officerID]lock, built once at startup
+	contest                  *model.Contest                // Contest info loaded from config, include all subjecs and chapters and questions
+	mu                       sync.RWMutex                  // guards mapOfficerToSubjectTests and mapTests
+	mapOfficerToSubjectTests map[int]map[int][]*model.Test // map[officerID][subjectID]attempts, oldest first
+	mapTests                 map[string]*model.Test        // map[testID]Test
+	store                    *store.Store                  // on-disk copy of tests and submissions
+	stopAutoSubmit           chan struct{}                 // closed to stop the auto-submit scheduler
+	autoSubmitDone           chan struct{}                 // closed once the scheduler has returned
+
+	accommodationMu sync.Mutex                           // guards accommodations and the accommodations file
+	accommodations  map[int]map[int]*model.Accommodation // map[officerID][subjectID]extra time, subject 0 for every subject
 }
 
 func NewContestService(conf *config.AppConfig) (*ContestService, error) {
@@ -26,31 +46,246 @@ func NewContestService(conf *config.AppConfig) (*ContestService, error) {
 	if err != nil {
 		return nil, err
 	}
+	fmt.Printf("Loaded %d officers from %s\n", len(conf.ListOfficer), conf.OfficerPath)
+
+	contestInfo, report := utils.ValidateContest(conf.ContestPath)
+	if report.HasErrors() {
+		return nil, report
+	}
+	if report.Warnings > 0 {
+		report.WriteText(os.Stdout)
+	}
+
+	st, err := store.Open(conf.DataPath)
+	if err != nil {
+		return nil, err
+	}
+
+	s, err := newContestService(conf, contestInfo, st)
+	if err != nil {
+		st.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+// newContestService builds the service from already loaded officers and
+// contest, restores the stored state and starts the auto-submit scheduler
+func newContestService(conf *config.AppConfig, contestInfo *model.Contest, st *store.Store) (*ContestService, error) {
 	mapSubjects := make(map[int]*model.Subject)
 	mapOfficers := make(map[int]*model.Officer)
+	officerLocks := make(map[int]*sync.Mutex)
+	withoutPIN := 0
 	for _, officer := range conf.ListOfficer {
 		mapOfficers[officer.ID] = officer
+		officerLocks[officer.ID] = &sync.Mutex{}
+		if officer.PIN == "" {
+			withoutPIN++
+		}
+	}
+	if withoutPIN > 0 {
+		fmt.Printf("Warning: %d officers have no PIN and will not be able to log in\n", withoutPIN)
 	}
-	fmt.Printf("Loaded %d officers from %s\n", len(conf.ListOfficer), conf.OfficerPath)
 	mapUnits := make(map[string]string)
 
-	contestInfo, err := utils.LoadContestInfo(conf.ContestPath)
-	if err != nil {
+	bankVersions := make(map[int]string)
+	for _, subject := range contestInfo.Subjects {
+		mapSubjects[subject.ID] = subject
+		bankVersions[subject.ID] = bankVersion(subject)
+		applySubjectRetakePolicy(conf, subject)
+	}
+
+	s := &ContestService{
+		conf:                     conf,
+		mapSubjects:              mapSubjects,
+		bankVersions:             bankVersions,
+		mapOfficers:              mapOfficers,
+		officerLocks:             officerLocks,
+		mapUnits:                 mapUnits,
+		contest:                  contestInfo,
+		mapOfficerToSubjectTests: make(map[int]map[int][]*model.Test),
+		mapTests:                 make(map[string]*model.Test),
+		store:                    st,
+	}
+	if err := s.restoreState(); err != nil {
+		return nil, err
+	}
+	if err := s.loadAccommodations(); err != nil {
 		return nil, err
 	}
 
-	for _, subject := range contestInfo.Subjects {
-		mapSubjects[subject.ID] = subject
+	s.stopAutoSubmit = make(chan struct{})
+	s.autoSubmitDone = make(chan struct{})
+	go func() {
+		defer close(s.autoSubmitDone)
+		s.runAutoSubmit(s.stopAutoSubmit)
+	}()
+	return s, nil
+}
+
+// restoreState reloads tests and submissions saved before the last shutdown
+func (s *ContestService) restoreState() error {
+	tests, err := s.store.LoadTests()
+	if err != nil {
+		return err
+	}
+	sort.SliceStable(tests, func(i, j int) bool {
+		return tests[i].Attempt < tests[j].Attempt
+	})
+	submissions, err := s.store.LoadSubmissions()
+	if err != nil {
+		return err
+	}
+	// Subjects are found by name, their IDs follow the folders
+	for _, test := range tests {
+		if test.Subject != nil {
+			test.Subject.ID = s.currentSubjectID(test.Subject.ID, test.Subject.Name)
+		}
+	}
+	for _, submission := range submissions {
+		submission.SubjectID = s.currentSubjectID(submission.SubjectID, submission.SubjectName)
+	}
+	if err := s.migrateQuestionIDs(tests, submissions); err != nil {
+		return fmt.Errorf("migrate question IDs: %w", err)
 	}
 
-	return &ContestService{
-		conf:                    conf,
-		mapSubjects:             mapSubjects,
-		mapOfficers:             mapOfficers,
-		mapUnits:                mapUnits,
-		contest:                 contestInfo,
-		mapOfficerToSubjectTest: make(map[int]map[int]*model.Test),
-	}, nil
+	for _, test := range tests {
+		if test.Officer == nil || test.Subject == nil || test.ResetAt > 0 {
+			continue
+		}
+		officer, ok := s.mapOfficers[test.Officer.ID]
+		if !ok {
+			fmt.Printf("Skipping stored test %s: officer %d no longer exists\n", test.ID, test.Officer.ID)
+			continue
+		}
+		if test.Subject.ID == 0 {
+			fmt.Printf("Skipping stored test %s: subject %s no longer exists\n", test.ID, test.Subject.Name)
+			continue
+		}
+		test.Officer = officer
+		s.addTest(test)
+	}
+
+	for _, submission := range submissions {
+		officer, ok := s.mapOfficers[submission.OfficerID]
+		if !ok {
+			fmt.Printf("Skipping stored submission %s: officer %d no longer exists\n", submission.ID, submission.OfficerID)
+			continue
+		}
+		if submission.SubjectID == 0 {
+			fmt.Printf("Skipping stored submission %s: subject %s no longer exists\n", submission.ID, submission.SubjectName)
+			continue
+		}
+		officer.ListSubmission = append(officer.ListSubmission, submission)
+	}
+	fmt.Printf("Restored %d tests and %d submissions from %s\n", len(tests), len(submissions), s.conf.DataPath)
+	return nil
+}
+
+// currentSubjectID returns the ID a subject saved with the given ID and name
+// has in the loaded contest, 0 when there is no subject of that name. Subject
+// IDs follow the order of the subject folders, so they move when a folder is
+// added, removed or renamed between restarts.
+func (s *ContestService) currentSubjectID(id int, name string) int {
+	if subject, ok := s.mapSubjects[id]; ok && (subject.Name == name || name == "") {
+		return id
+	}
+	for _, subject := range s.contest.Subjects {
+		if subject.Name == name {
+			return subject.ID
+		}
+	}
+	return 0
+}
+
+// Close stops the auto-submit scheduler and releases the data file
+func (s *ContestService) Close() error {
+	close(s.stopAutoSubmit)
+	<-s.autoSubmitDone
+	return s.store.Close()
+}
+
+// lockOfficer takes the lock of an officer and returns the officer with the
+// function releasing it
+func (s *ContestService) lockOfficer(officerID int) (*model.Officer, func(), error) {
+	officer, ok := s.mapOfficers[officerID]
+	if !ok {
+		return nil, nil, ErrOfficerNotFound
+	}
+	lock := s.officerLocks[officerID]
+	lock.Lock()
+	return officer, lock.Unlock, nil
+}
+
+// addTest records a new attempt of an officer in a subject
+func (s *ContestService) addTest(test *model.Test) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	officerID := test.Officer.ID
+	if _, ok := s.mapOfficerToSubjectTests[officerID]; !ok {
+		s.mapOfficerToSubjectTests[officerID] = make(map[int][]*model.Test)
+	}
+	s.mapOfficerToSubjectTests[officerID][test.Subject.ID] = append(s.mapOfficerToSubjectTests[officerID][test.Subject.ID], test)
+	s.mapTests[test.ID] = test
+}
+
+// removeTest takes a reset test out of the attempts of its officer
+func (s *ContestService) removeTest(test *model.Test) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	officerID, subjectID := test.Officer.ID, test.Subject.ID
+	var kept []*model.Test
+	for _, attempt := range s.mapOfficerToSubjectTests[officerID][subjectID] {
+		if attempt != test {
+			kept = append(kept, attempt)
+		}
+	}
+	s.mapOfficerToSubjectTests[officerID][subjectID] = kept
+	delete(s.mapTests, test.ID)
+}
+
+// attempts returns the tests of an officer in a subject, oldest first
+func (s *ContestService) attempts(officerID int, subjectID int) []*model.Test {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return append([]*model.Test(nil), s.mapOfficerToSubjectTests[officerID][subjectID]...)
+}
+
+// findTest returns the test with the given ID if it belongs to the officer
+func (s *ContestService) findTest(officerID int, testID string) *model.Test {
+	s.mu.RLock()
+	test, ok := s.mapTests[testID]
+	s.mu.RUnlock()
+	if !ok || test.Officer.ID != officerID {
+		return nil
+	}
+	return test
+}
+
+// copyOfficer returns a copy of the officer that can be read after the
+// officer lock is released
+func copyOfficer(officer *model.Officer) *model.Officer {
+	officerCopy := *officer
+	officerCopy.ListSubmission = append([]*model.Submission(nil), officer.ListSubmission...)
+	return &officerCopy
+}
+
+// copyTest returns a copy of the test that can be read after the officer
+// lock is released. The officer is reduced to its identity, the questions of
+// a generated test never change.
+func copyTest(test *model.Test) *model.Test {
+	testCopy := *test
+	testCopy.RemainingTime = test.Remaining(time.Now().Unix())
+	if test.Officer != nil {
+		testCopy.Officer = &model.Officer{
+			ID:       test.Officer.ID,
+			Name:     test.Officer.Name,
+			Unit:     test.Officer.Unit,
+			Rank:     test.Officer.Rank,
+			Position: test.Officer.Position,
+		}
+	}
+	return &testCopy
 }
 
 func (s *ContestService) GetContestInfo() *model.Contest {
@@ -69,30 +304,66 @@ func (s *ContestService) GetAllUnits() []string {
 // GetAllOfficers returns all officers with their unit information
 func (s *ContestService) GetAllOfficers() []*model.Officer {
 	officers := make([]*model.Officer, 0, len(s.mapOfficers))
-	for _, officer := range s.mapOfficers {
-		officer.Score = s.caculateTotalScoreOfOffices(officer)
-		officers = append(officers, officer)
+	for officerID := range s.mapOfficers {
+		officer, unlock, _ := s.lockOfficer(officerID)
+		officerCopy := copyOfficer(officer)
+		unlock()
+		officerCopy.Score = s.caculateTotalScoreOfOffices(officerCopy)
+		officers = append(officers, officerCopy)
 	}
 	return officers
 }
 
+// caculateTotalScoreOfOffices sums the officer's score of every subject,
+// counting the attempts of a subject by its retake policy
 func (s *ContestService) caculateTotalScoreOfOffices(office *model.Officer) float32 {
+	var totalScore float32
+	for _, subject := range s.SubjectScores(office) {
+		totalScore += subject.Score
+	}
+	return totalScore
+}
+
+// SubjectScores returns the score the officer keeps in every subject with a
+// counted submission, ordered by subject ID. The officer must be a copy or be
+// locked by the caller.
+func (s *ContestService) SubjectScores(office *model.Officer) []*model.SubjectScore {
 	if office == nil || len(office.ListSubmission) == 0 {
-		return 0
+		return nil
 	}
-	var totalScore float32
+	bySubject := make(map[int][]*model.Submission)
 	for _, submission := range office.ListSubmission {
-		totalScore += submission.Score
+		if !submission.Counts() {
+			continue
+		}
+		bySubject[submission.SubjectID] = append(bySubject[submission.SubjectID], submission)
+	}
+	scores := make([]*model.SubjectScore, 0, len(bySubject))
+	for subjectID, submissions := range bySubject {
+		policy := s.conf.RetakePolicyFor(submissions[0].SubjectName)
+		last := submissions[len(submissions)-1]
+		scores = append(scores, &model.SubjectScore{
+			SubjectID:   subjectID,
+			SubjectName: last.SubjectName,
+			Score:       subjectScore(policy.Scoring, submissions),
+			Attempts:    len(submissions),
+			SubmittedAt: last.SubmittedAt,
+		})
 	}
-	return totalScore
+	sort.Slice(scores, func(i, j int) bool {
+		return scores[i].SubjectID < scores[j].SubjectID
+	})
+	return scores
 }
 
 // GetOfficerByID returns an officer by ID with unit information
 func (s *ContestService) GetOfficerByID(officerID int) (*model.Officer, error) {
-	officer, exists := s.mapOfficers[officerID]
-	if !exists {
-		return nil, fmt.Errorf("officer not found")
+	officer, unlock, err := s.lockOfficer(officerID)
+	if err != nil {
+		return nil, err
 	}
+	officer = copyOfficer(officer)
+	unlock()
 
 	// Add unit information to officer
 	if unit, exists := s.mapUnits[officer.Unit]; exists {
@@ -102,10 +373,51 @@ func (s *ContestService) GetOfficerByID(officerID int) (*model.Officer, error) {
 	return officer, nil
 }
 
-// GetAllSubjects returns all subjects without questions and chapters
+// AuthenticateOfficer checks an officer's PIN. Officers without a PIN in the
+// officer file cannot log in.
+func (s *ContestService) AuthenticateOfficer(officerID int, pin string) (*model.Officer, error) {
+	officer, exists := s.mapOfficers[officerID]
+	if !exists || officer.PIN == "" || pin == "" {
+		return nil, ErrInvalidCredentials
+	}
+	if subtle.ConstantTimeCompare([]byte(officer.PIN), []byte(pin)) != 1 {
+		return nil, ErrInvalidCredentials
+	}
+	return officer, nil
+}
+
+// applySubjectRetakePolicy makes the retake policy of a subject's manifest
+// the subject's policy in the config, unless config.json already sets one for
+// the subject. Fields the manifest leaves out keep the default policy.
+func applySubjectRetakePolicy(conf *config.AppConfig, subject *model.Subject) {
+	manifest := subject.RetakePolicy
+	if manifest == nil {
+		return
+	}
+	if _, ok := conf.SubjectRetakePolicies[subject.Name]; ok {
+		return
+	}
+	policy := conf.RetakePolicy
+	if manifest.MaxAttempts > 0 {
+		policy.MaxAttempts = manifest.MaxAttempts
+	}
+	if manifest.Cooldown > 0 {
+		policy.Cooldown = manifest.Cooldown
+	}
+	if manifest.Scoring != "" {
+		policy.Scoring = manifest.Scoring
+	}
+	if conf.SubjectRetakePolicies == nil {
+		conf.SubjectRetakePolicies = make(map[string]config.RetakePolicy)
+	}
+	conf.SubjectRetakePolicies[subject.Name] = policy
+}
+
+// GetAllSubjects returns all subjects without questions and chapters, in
+// the order of the contest
 func (s *ContestService) GetAllSubjects() []*model.Subject {
 	subjects := make([]*model.Subject, 0, len(s.mapSubjects))
-	for _, subject := range s.mapSubjects {
+	for _, subject := range s.contest.Subjects {
 		// Create a copy without chapters (which contain questions)
 		subjectInfo := &model.Subject{
 			ID:              subject.ID,
@@ -114,6 +426,7 @@ func (s *ContestService) GetAllSubjects() []*model.Subject {
 			ContestID:       subject.ContestID,
 			NumQuestionTest: subject.NumQuestionTest,
 			TestTime:        subject.TestTime,
+			Order:           subject.Order,
 			Chapters:        nil, // Explicitly set to nil to exclude chapters and questions
 		}
 		subjects = append(subjects, subjectInfo)
@@ -123,49 +436,53 @@ func (s *ContestService) GetAllSubjects() []*model.Subject {
 
 // Retrive list question from a subject, total question is field NumQuestionTest and each chapter has NumQuestionTest, NumQuestionTest is total question of Chapter.NumQuestionTest
 func (s *ContestService) GetSubjectTestForOfficer(officerID int, subjectID int) (*model.Test, error) {
-	if s.mapOfficerToSubjectTest == nil {
-		s.mapOfficerToSubjectTest = make(map[int]map[int]*model.Test)
-	}
-	if _, ok := s.mapOfficerToSubjectTest[officerID]; !ok {
-		s.mapOfficerToSubjectTest[officerID] = make(map[int]*model.Test)
-	}
-	if _, ok := s.mapOfficerToSubjectTest[officerID][subjectID]; ok {
-		// Check if test is started and not expired
-		if s.mapOfficerToSubjectTest[officerID][subjectID].StartTime > 0 {
-			elapsed := time.Now().Unix() - s.mapOfficerToSubjectTest[officerID][subjectID].StartTime
-			if elapsed > int64(s.mapOfficerToSubjectTest[officerID][subjectID].Duration) {
-				return nil, fmt.Errorf("test is expired")
+	officer, unlock, err := s.lockOfficer(officerID)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	attempts := s.attempts(officerID, subjectID)
+	if len(attempts) > 0 {
+		existing := attempts[len(attempts)-1]
+		policy := s.conf.RetakePolicyFor(existing.Subject.Name)
+		if !existing.IsFinished || len(attempts) >= policy.MaxAttempts {
+			// A finished test is returned as finished. A running one expires
+			// with the grace period, like saving and submitting, and a paused
+			// one is not running out.
+			deadline := existing.Deadline()
+			if !existing.IsFinished && existing.PausedAt == 0 && deadline > 0 && time.Now().Unix() > deadline+int64(s.conf.GracePeriod) {
+				return nil, ErrTestExpired
 			}
+			return copyTest(existing), nil // Return existing test if it exists
+		}
+		// The last attempt is finished and the policy allows another one
+		if time.Now().Unix() < existing.FinishedAt+int64(policy.Cooldown)*60 {
+			return nil, ErrRetakeNotAvailable
 		}
-		return s.mapOfficerToSubjectTest[officerID][subjectID], nil // Return existing test if it exists
 	}
 
 	subject, ok := s.mapSubjects[subjectID]
 	if !ok {
-		return nil, fmt.Errorf("subject not found")
+		return nil, ErrSubjectNotFound
 	}
 
-	rand.NewSource(time.Now().UnixNano())
-
-	officer, ok := s.mapOfficers[officerID]
-	if !ok {
-		return nil, fmt.Errorf("officer not found")
-	}
 	if subject.NumQuestionTest <= 0 {
-		return nil, fmt.Errorf("subject does not have enough questions for test")
+		return nil, fmt.Errorf("subject %s: %w", subject.Name, ErrNotEnoughQuestions)
 	}
-	listQuestions := []*model.Question{}
-	for _, chapter := range subject.Chapters {
-		if chapter.NumQuestionTest <= 0 {
-			continue
-		}
-		rand.Shuffle(len(chapter.Questions), func(i, j int) {
-			chapter.Questions[i], chapter.Questions[j] = chapter.Questions[j], chapter.Questions[i]
-		})
-		if len(chapter.Questions) < chapter.NumQuestionTest {
-			return nil, fmt.Errorf("not enough questions in chapter %s", chapter.Name)
-		}
-		listQuestions = append(listQuestions, chapter.Questions[:chapter.NumQuestionTest]...)
+	// Retakes avoid the questions of earlier attempts where the bank allows
+	excluded := seenQuestionIDs(attempts)
+	seed := newSeed()
+	listQuestions, err := generatePaper(subject, seed, excluded)
+	if err != nil {
+		return nil, err
+	}
+
+	// Officers with an accommodation get extra time
+	duration := subject.TestTime * 60 // Convert minutes to seconds
+	extraTime := 0
+	if accommodation := s.accommodationFor(officerID, subjectID); accommodation != nil {
+		extraTime = accommodation.ExtraSeconds(duration)
 	}
 
 	test := &model.Test{
@@ -179,129 +496,356 @@ func (s *ContestService) GetSubjectTestForOfficer(officerID int, subjectID int)
 		},
 		Questions:     listQuestions,
 		Officer:       officer,
-		Duration:      subject.TestTime * 60, // Convert minutes to seconds
-		RemainingTime: subject.TestTime * 60, // Initially same as duration
-		ID:            rand.Intn(1000),       // Random ID for the test
+		Duration:      duration + extraTime,
+		RemainingTime: duration + extraTime, // Initially same as duration
+		ExtraTime:     extraTime,
+		ID:            utils.NewID(),
+		Seed:          seed,
+		BankVersion:   s.bankVersions[subject.ID],
+		Attempt:       len(attempts) + 1,
+
+		ExcludedQuestionIDs: excluded,
 	}
 
+	if err := s.store.SaveTest(test); err != nil {
+		return nil, fmt.Errorf("failed to save test: %w", err)
+	}
 	// Store the test for this officer and subject
-	s.mapOfficerToSubjectTest[officerID][subjectID] = test
+	s.addTest(test)
 
-	return test, nil
+	return copyTest(test), nil
 }
 
-// StartTest starts a test for an officer by setting the start time
-func (s *ContestService) StartTest(officerID int, testID int) (*model.Test, error) {
-	// Find the test for the officer
-	if s.mapOfficerToSubjectTest == nil {
-		return nil, fmt.Errorf("no tests found")
+// GetTestForOfficer returns the latest test generated for an officer in a subject
+func (s *ContestService) GetTestForOfficer(officerID int, subjectID int) (*model.Test, error) {
+	_, unlock, err := s.lockOfficer(officerID)
+	if err != nil {
+		return nil, err
 	}
+	defer unlock()
 
-	if _, ok := s.mapOfficerToSubjectTest[officerID]; !ok {
-		return nil, fmt.Errorf("no tests found for officer")
+	attempts := s.attempts(officerID, subjectID)
+	if len(attempts) == 0 {
+		return nil, ErrTestNotFound
 	}
+	return copyTest(attempts[len(attempts)-1]), nil
+}
 
-	// Search for the test with the given testID
-	var foundTest *model.Test
-	for _, test := range s.mapOfficerToSubjectTest[officerID] {
-		if test.ID == testID {
-			foundTest = test
-			break
-		}
+// AuditTest regenerates the paper of a stored test from its recorded seed and
+// compares it with the questions the officer actually got
+func (s *ContestService) AuditTest(testID string) (*model.TestAudit, error) {
+	s.mu.RLock()
+	stored, ok := s.mapTests[testID]
+	s.mu.RUnlock()
+	if !ok {
+		return nil, ErrTestNotFound
+	}
+	_, unlock, err := s.lockOfficer(stored.Officer.ID)
+	if err != nil {
+		return nil, err
 	}
+	test := copyTest(stored)
+	unlock()
 
+	if test.Seed == 0 {
+		return nil, fmt.Errorf("test %s: %w", test.ID, ErrNoSeed)
+	}
+	subject, ok := s.mapSubjects[test.Subject.ID]
+	if !ok {
+		return nil, ErrSubjectNotFound
+	}
+	regenerated, err := generatePaper(subject, test.Seed, test.ExcludedQuestionIDs)
+	if err != nil {
+		return nil, err
+	}
+	currentVersion := s.bankVersions[subject.ID]
+	return &model.TestAudit{
+		Test:               test,
+		Regenerated:        regenerated,
+		CurrentBankVersion: currentVersion,
+		BankChanged:        currentVersion != test.BankVersion,
+		Matches:            samePaper(test.Questions, regenerated),
+	}, nil
+}
+
+// StartTest starts a test for an officer by setting the start time
+func (s *ContestService) StartTest(officerID int, testID string) (*model.Test, error) {
+	_, unlock, err := s.lockOfficer(officerID)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	// Search for the test with the given testID
+	foundTest := s.findTest(officerID, testID)
 	if foundTest == nil {
-		return nil, fmt.Errorf("test not found")
+		return nil, ErrTestNotFound
+	}
+	if foundTest.InvalidatedAt > 0 {
+		return nil, ErrTestInvalidated
 	}
 
 	// Check if test is already started
 	if foundTest.StartTime > 0 {
-		return nil, fmt.Errorf("test already started")
+		return nil, ErrTestAlreadyStarted
 	}
 
 	// Set the start time
 	foundTest.StartTime = time.Now().Unix()
+	if err := s.store.SaveTest(foundTest); err != nil {
+		foundTest.StartTime = 0
+		return nil, fmt.Errorf("failed to save test: %w", err)
+	}
 
-	return foundTest, nil
+	return copyTest(foundTest), nil
 }
 
-// SubmitTest submits test answers and calculates the score
-func (s *ContestService) SubmitTest(officerID int, testID int, answers map[string]string) (*model.Submission, error) {
-	// Find the test for the officer
-	if s.mapOfficerToSubjectTest == nil {
-		return nil, fmt.Errorf("no tests found")
+// GetTestClock returns the server time and the time left in an officer's test
+func (s *ContestService) GetTestClock(officerID int, testID string) (*model.TestClock, error) {
+	_, unlock, err := s.lockOfficer(officerID)
+	if err != nil {
+		return nil, err
 	}
+	defer unlock()
 
-	if _, ok := s.mapOfficerToSubjectTest[officerID]; !ok {
-		return nil, fmt.Errorf("no tests found for officer")
+	foundTest := s.findTest(officerID, testID)
+	if foundTest == nil {
+		return nil, ErrTestNotFound
+	}
+	now := time.Now()
+	return &model.TestClock{
+		ServerTime:    now.Unix(),
+		ServerTimeMs:  now.UnixMilli(),
+		TestID:        foundTest.ID,
+		StartTime:     foundTest.StartTime,
+		Deadline:      foundTest.Deadline(),
+		RemainingTime: foundTest.Remaining(now.Unix()),
+		GracePeriod:   s.conf.GracePeriod,
+		IsFinished:    foundTest.IsFinished,
+		Paused:        foundTest.PausedAt > 0,
+	}, nil
+}
+
+// GetTestQuestions returns a started test for its questions. The questions
+// are only released while answers are accepted.
+func (s *ContestService) GetTestQuestions(officerID int, testID string) (*model.Test, error) {
+	_, unlock, err := s.lockOfficer(officerID)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	foundTest := s.findTest(officerID, testID)
+	if foundTest == nil {
+		return nil, ErrTestNotFound
+	}
+	if foundTest.InvalidatedAt > 0 {
+		return nil, ErrTestInvalidated
+	}
+	if foundTest.StartTime == 0 {
+		return nil, ErrTestNotStarted
+	}
+	if foundTest.IsFinished {
+		return nil, ErrTestAlreadySubmitted
 	}
-	// validate officerID and testID
-	if _, ok := s.mapOfficers[officerID]; !ok {
-		return nil, fmt.Errorf("officer not found")
+	if foundTest.PausedAt > 0 {
+		return nil, ErrTestPaused
 	}
+	if time.Now().Unix() > foundTest.Deadline()+int64(s.conf.GracePeriod) {
+		return nil, ErrTestExpired
+	}
+	return copyTest(foundTest), nil
+}
 
-	// Search for the test with the given testID
-	var foundTest *model.Test
-	for _, test := range s.mapOfficerToSubjectTest[officerID] {
-		if test.ID == testID {
-			foundTest = test
-			break
-		}
+// SubmitTest submits test answers and calculates the score
+//
+// A non-empty idempotencyKey makes retries safe: repeating the call with the
+// same key and answers returns the original submission, while the same key
+// with other answers fails with ErrIdempotencyKeyReused.
+func (s *ContestService) SubmitTest(officerID int, testID string, answers map[string]string, idempotencyKey string) (*model.Submission, error) {
+	_, unlock, err := s.lockOfficer(officerID)
+	if err != nil {
+		return nil, err
 	}
+	defer unlock()
 
+	// Search for the test with the given testID
+	foundTest := s.findTest(officerID, testID)
 	if foundTest == nil {
-		return nil, fmt.Errorf("test not found")
+		return nil, ErrTestNotFound
+	}
+	if foundTest.InvalidatedAt > 0 {
+		return nil, ErrTestInvalidated
 	}
 
 	// Check if test has started
 	if foundTest.StartTime == 0 {
-		return nil, fmt.Errorf("test has not been started yet")
+		return nil, ErrTestNotStarted
 	}
 
-	// Check if test is already finished
+	// Reject answers that do not fit the test before anything is recorded
+	submitted, err := validateAnswers(foundTest, answers)
+	if err != nil {
+		return nil, err
+	}
+	hash := payloadHash(submitted)
+
+	// Check if test is already finished, answering a retry with the original
 	if foundTest.IsFinished {
-		return nil, fmt.Errorf("test has already been submitted")
+		if previous := submissionOf(foundTest); idempotencyKey != "" && previous != nil && previous.IdempotencyKey == idempotencyKey {
+			if previous.PayloadHash != hash {
+				return nil, ErrIdempotencyKeyReused
+			}
+			return previous, nil
+		}
+		return nil, ErrTestAlreadySubmitted
+	}
+	if foundTest.PausedAt > 0 {
+		return nil, ErrTestPaused
 	}
 
-	// Calculate score
-	totalQuestions := len(foundTest.Questions)
-	correctAnswers := 0
+	// Submitted answers override the saved ones
+	graded := savedAnswerLetters(foundTest)
 
-	// Create a map of question IDs to correct answers for easy lookup
-	questionAnswers := make(map[string]string)
-	for _, question := range foundTest.Questions {
-		questionAnswers[fmt.Sprintf("%d", question.ID)] = question.Correct
+	// The server clock decides whether the answers still count
+	now := time.Now().Unix()
+	deadline := foundTest.Deadline()
+	status := model.SubmissionStatusOnTime
+	switch {
+	case now > deadline+int64(s.conf.GracePeriod):
+		// Only the answers saved in time are graded
+		status = model.SubmissionStatusLate
+		submitted = nil
+	case now > deadline:
+		status = model.SubmissionStatusInGrace
 	}
+	maps.Copy(graded, submitted)
 
-	// Check answers and count correct ones
-	for questionIDStr, userAnswer := range answers {
-		if correctAnswer, exists := questionAnswers[questionIDStr]; exists {
-			if strings.EqualFold(userAnswer, correctAnswer) {
-				correctAnswers++
-			}
+	return s.finalizeTest(foundTest, graded, status, now, idempotencyKey, hash)
+}
+
+// SaveAnswers records answers of a started test without submitting it, one
+// question or many at once. A blank answer clears the question. Answers can
+// be saved until the deadline plus the grace period. A retry with the same
+// non-empty idempotencyKey as the last save returns the saved answers without
+// saving again.
+func (s *ContestService) SaveAnswers(officerID int, testID string, answers map[string]string, idempotencyKey string) (map[string]model.SavedAnswer, error) {
+	_, unlock, err := s.lockOfficer(officerID)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	foundTest := s.findTest(officerID, testID)
+	if foundTest == nil {
+		return nil, ErrTestNotFound
+	}
+	if foundTest.InvalidatedAt > 0 {
+		return nil, ErrTestInvalidated
+	}
+	valid, err := validateAnswers(foundTest, answers)
+	if err != nil {
+		return nil, err
+	}
+	hash := payloadHash(valid)
+	if idempotencyKey != "" && idempotencyKey == foundTest.LastSaveKey {
+		// A retry must not overwrite answers saved after the original call
+		if foundTest.LastSaveHash != hash {
+			return nil, ErrIdempotencyKeyReused
+		}
+		return maps.Clone(foundTest.SavedAnswers), nil
+	}
+	if foundTest.StartTime == 0 {
+		return nil, ErrTestNotStarted
+	}
+	if foundTest.IsFinished {
+		return nil, ErrTestAlreadySubmitted
+	}
+	if foundTest.PausedAt > 0 {
+		return nil, ErrTestPaused
+	}
+	now := time.Now().Unix()
+	if now > foundTest.Deadline()+int64(s.conf.GracePeriod) {
+		return nil, ErrTestExpired
+	}
+
+	// The map is replaced rather than modified, copies handed out earlier
+	// keep their own
+	previous := foundTest.SavedAnswers
+	saved := make(map[string]model.SavedAnswer, len(previous)+len(valid))
+	maps.Copy(saved, previous)
+	for questionID, answer := range valid {
+		saved[questionID] = model.SavedAnswer{Answer: answer, SavedAt: now}
+	}
+	previousKey, previousHash := foundTest.LastSaveKey, foundTest.LastSaveHash
+	foundTest.LastSaveKey, foundTest.LastSaveHash = idempotencyKey, hash
+	foundTest.SavedAnswers = saved
+	if err := s.store.SaveTest(foundTest); err != nil {
+		foundTest.SavedAnswers = previous
+		foundTest.LastSaveKey, foundTest.LastSaveHash = previousKey, previousHash
+		return nil, fmt.Errorf("failed to save test: %w", err)
+	}
+	return maps.Clone(saved), nil
+}
+
+// finalizeTest grades the answers, marks the test finished and records the
+// submission with the given status and the idempotency key of the request,
+// if any. The caller holds the officer lock.
+func (s *ContestService) finalizeTest(test *model.Test, answers map[string]string, status string, now int64, idempotencyKey, payloadHash string) (*model.Submission, error) {
+	answers, err := normalizeAnswers(test, answers)
+	if err != nil {
+		return nil, err
+	}
+
+	// Calculate score
+	totalQuestions := len(test.Questions)
+	correctAnswers := 0
+
+	// Map the displayed letters back to the bank's letters before comparing
+	canonicalAnswers := make(map[string]string, len(answers))
+	for _, question := range test.Questions {
+		questionID := fmt.Sprintf("%d", question.ID)
+		canonical := question.CanonicalLetter(answers[questionID])
+		canonicalAnswers[questionID] = canonical
+		if canonical != "" && strings.EqualFold(canonical, strings.TrimSpace(question.Correct)) {
+			correctAnswers++
 		}
 	}
 
 	// Calculate score as percentage
 	score := float32(correctAnswers) / float32(totalQuestions) * 10
 
-	// Mark test as finished
-	foundTest.IsFinished = true
-
 	// Create submission record
 	submission := &model.Submission{
-		ID:          rand.Intn(10000), // Random ID for submission
-		OfficerID:   officerID,
-		TestID:      testID,
-		Answers:     answers,
-		Score:       score,
-		SubmittedAt: time.Now().Unix(),
-		SubjectID:   foundTest.Subject.ID,
-		SubjectName: foundTest.Subject.Name,
+		ID:               utils.NewID(),
+		OfficerID:        test.Officer.ID,
+		TestID:           test.ID,
+		Answers:          answers,
+		CanonicalAnswers: canonicalAnswers,
+		Score:            score,
+		SubmittedAt:      now,
+		SubjectID:        test.Subject.ID,
+		SubjectName:      test.Subject.Name,
+		Deadline:         test.Deadline(),
+		Status:           status,
+		Attempt:          test.Attempt,
+		IdempotencyKey:   idempotencyKey,
+		PayloadHash:      payloadHash,
+	}
+
+	// Mark test as finished, no answer save can be retried anymore
+	lastSaveKey, lastSaveHash := test.LastSaveKey, test.LastSaveHash
+	test.IsFinished = true
+	test.FinishedAt = now
+	test.LastSaveKey, test.LastSaveHash = "", ""
+	if err := s.store.SaveSubmission(test, submission); err != nil {
+		test.IsFinished = false
+		test.FinishedAt = 0
+		test.LastSaveKey, test.LastSaveHash = lastSaveKey, lastSaveHash
+		return nil, fmt.Errorf("failed to save submission: %w", err)
 	}
+
 	// Add submission to officer's list
-	if officer, exists := s.mapOfficers[officerID]; exists {
-		officer.ListSubmission = append(officer.ListSubmission, submission)
-	}
+	test.Officer.ListSubmission = append(test.Officer.ListSubmission, submission)
 	return submission, nil
 }
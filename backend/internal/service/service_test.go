@@ -0,0 +1,862 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/lehaisonagentai3/free-contest/backend/internal/config"
+	"github.com/lehaisonagentai3/free-contest/backend/internal/model"
+	"github.com/lehaisonagentai3/free-contest/backend/internal/store"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// newTestService builds a service over an in-memory bank of one subject with
+// two chapters and the given number of officers
+func newTestService(t *testing.T, numOfficers int) *ContestService {
+	t.Helper()
+	conf := &config.AppConfig{DataPath: filepath.Join(t.TempDir(), "contest.db")}
+	for id := 1; id <= numOfficers; id++ {
+		conf.ListOfficer = append(conf.ListOfficer, &model.Officer{ID: id, Name: fmt.Sprintf("Cán bộ %d", id), PIN: "1234"})
+	}
+	s := openTestService(t, conf)
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+// openTestService builds a service over the in-memory bank and the data
+// file of the config
+func openTestService(t *testing.T, conf *config.AppConfig) *ContestService {
+	t.Helper()
+	subject := &model.Subject{ID: 1, Name: "Điều lệnh", TestTime: 30}
+	for chapterID := 1; chapterID <= 2; chapterID++ {
+		chapter := &model.Chapter{ID: chapterID, SubjectID: subject.ID, Name: fmt.Sprintf("Chương %d", chapterID), NumQuestionTest: 5}
+		for i := 0; i < 20; i++ {
+			chapter.Questions = append(chapter.Questions, &model.Question{
+				ID:      chapterID*100 + i,
+				Content: fmt.Sprintf("Câu %d.%d", chapterID, i),
+				AnswerA: "Phương án A", AnswerB: "Phương án B", AnswerC: "Phương án C", AnswerD: "Phương án D",
+				Correct: string(rune('A' + i%4)),
+			})
+		}
+		chapter.TotalQuestions = len(chapter.Questions)
+		subject.NumQuestionTest += chapter.NumQuestionTest
+		subject.Chapters = append(subject.Chapters, chapter)
+	}
+	contest := &model.Contest{ID: 1, Name: "Kỳ thi", Subjects: []*model.Subject{subject}}
+
+	st, err := store.Open(conf.DataPath)
+	if err != nil {
+		t.Fatalf("Failed to open store: %v", err)
+	}
+	s, err := newContestService(conf, contest, st)
+	if err != nil {
+		st.Close()
+		t.Fatalf("Failed to create service: %v", err)
+	}
+	return s
+}
+
+// TestConcurrentExamTraffic simulates a room of officers starting and
+// submitting at once. Run it with -race.
+func TestConcurrentExamTraffic(t *testing.T) {
+	const numOfficers = 300
+	s := newTestService(t, numOfficers)
+
+	bankOrder := make(map[int][]int)
+	for _, chapter := range s.mapSubjects[1].Chapters {
+		for _, question := range chapter.Questions {
+			bankOrder[chapter.ID] = append(bankOrder[chapter.ID], question.ID)
+		}
+	}
+
+	stop := make(chan struct{})
+	var readers sync.WaitGroup
+	readers.Add(1)
+	go func() {
+		defer readers.Done()
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+				s.GetAllOfficers()
+				s.autoSubmitExpired(time.Now().Unix())
+			}
+		}
+	}()
+
+	var wg sync.WaitGroup
+	errs := make(chan error, numOfficers)
+	for officerID := 1; officerID <= numOfficers; officerID++ {
+		wg.Add(1)
+		go func(officerID int) {
+			defer wg.Done()
+			// A second tab asking for the same test must get the same one
+			var other *model.Test
+			var otherErr error
+			done := make(chan struct{})
+			go func() {
+				defer close(done)
+				other, otherErr = s.GetSubjectTestForOfficer(officerID, 1)
+			}()
+			test, err := s.GetSubjectTestForOfficer(officerID, 1)
+			<-done
+			if err != nil || otherErr != nil {
+				errs <- fmt.Errorf("officer %d: get test: %v %v", officerID, err, otherErr)
+				return
+			}
+			if test.ID != other.ID {
+				errs <- fmt.Errorf("officer %d: got two different tests", officerID)
+				return
+			}
+			if _, err := s.StartTest(officerID, test.ID); err != nil {
+				errs <- fmt.Errorf("officer %d: start: %v", officerID, err)
+				return
+			}
+			answers := make(map[string]string)
+			for _, question := range test.Questions {
+				answers[fmt.Sprint(question.ID)] = question.DisplayedLetter(question.Correct)
+			}
+			submission, err := s.SubmitTest(officerID, test.ID, answers, "")
+			if err != nil {
+				errs <- fmt.Errorf("officer %d: submit: %v", officerID, err)
+				return
+			}
+			if submission.Score != 10 {
+				errs <- fmt.Errorf("officer %d: expected score 10, got %v", officerID, submission.Score)
+			}
+		}(officerID)
+	}
+	wg.Wait()
+	close(stop)
+	readers.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+
+	for _, officer := range s.GetAllOfficers() {
+		if len(officer.ListSubmission) != 1 {
+			t.Errorf("officer %d: expected 1 submission, got %d", officer.ID, len(officer.ListSubmission))
+		}
+	}
+	for _, chapter := range s.mapSubjects[1].Chapters {
+		for i, question := range chapter.Questions {
+			if question.ID != bankOrder[chapter.ID][i] {
+				t.Fatalf("chapter %d: question bank was reordered", chapter.ID)
+			}
+		}
+	}
+}
+
+func TestSelectQuestionsLeavesBankUntouched(t *testing.T) {
+	s := newTestService(t, 1)
+	subject := s.mapSubjects[1]
+	first := subject.Chapters[0].Questions[0]
+
+	a, err := selectQuestions(subject, testRand(42), nil)
+	if err != nil {
+		t.Fatalf("Failed to select questions: %v", err)
+	}
+	b, _ := selectQuestions(subject, testRand(42), nil)
+	if len(a) != subject.NumQuestionTest {
+		t.Fatalf("expected %d questions, got %d", subject.NumQuestionTest, len(a))
+	}
+	for i := range a {
+		if a[i].ID != b[i].ID {
+			t.Fatalf("same seed gave different papers at %d: %d != %d", i, a[i].ID, b[i].ID)
+		}
+		// The test owns its questions
+		a[i].Content = "changed"
+	}
+	if subject.Chapters[0].Questions[0] != first || first.Content == "changed" {
+		t.Fatal("question bank was modified")
+	}
+}
+
+func TestShuffledOptionsScoreAgainstBankLetter(t *testing.T) {
+	s := newTestService(t, 1)
+	test, err := s.GetSubjectTestForOfficer(1, 1)
+	if err != nil {
+		t.Fatalf("Failed to get test: %v", err)
+	}
+	if _, err := s.StartTest(1, test.ID); err != nil {
+		t.Fatalf("Failed to start test: %v", err)
+	}
+
+	answers := make(map[string]string)
+	for _, question := range test.Questions {
+		displayed := question.DisplayedLetter(question.Correct)
+		// The option shown under the displayed letter is the bank's correct option
+		shown := map[string]string{"A": question.AnswerA, "B": question.AnswerB, "C": question.AnswerC, "D": question.AnswerD}[displayed]
+		if shown != "Phương án "+question.Correct {
+			t.Fatalf("question %d: letter %s shows %q, expected the option %s", question.ID, displayed, shown, question.Correct)
+		}
+		answers[fmt.Sprint(question.ID)] = displayed
+	}
+
+	submission, err := s.SubmitTest(1, test.ID, answers, "")
+	if err != nil {
+		t.Fatalf("Failed to submit: %v", err)
+	}
+	if submission.Score != 10 {
+		t.Errorf("expected score 10, got %v", submission.Score)
+	}
+	for _, question := range test.Questions {
+		questionID := fmt.Sprint(question.ID)
+		if submission.CanonicalAnswers[questionID] != question.Correct {
+			t.Errorf("question %s: canonical answer %q, expected %q", questionID, submission.CanonicalAnswers[questionID], question.Correct)
+		}
+	}
+}
+
+func TestAuditRegeneratesSamePaper(t *testing.T) {
+	s := newTestService(t, 1)
+	test, err := s.GetSubjectTestForOfficer(1, 1)
+	if err != nil {
+		t.Fatalf("Failed to get test: %v", err)
+	}
+	if test.Seed == 0 || test.BankVersion == "" {
+		t.Fatalf("seed and bank version not recorded: %d %q", test.Seed, test.BankVersion)
+	}
+
+	audit, err := s.AuditTest(test.ID)
+	if err != nil {
+		t.Fatalf("Failed to audit test: %v", err)
+	}
+	if !audit.Matches || audit.BankChanged {
+		t.Errorf("expected identical paper from unchanged bank, got matches=%v bank_changed=%v", audit.Matches, audit.BankChanged)
+	}
+
+	// A bank shrunk below the chapter's share cannot regenerate the paper
+	chapter := s.mapSubjects[1].Chapters[0]
+	chapter.Questions = chapter.Questions[:chapter.NumQuestionTest-1]
+	if _, err := s.AuditTest(test.ID); !errors.Is(err, ErrNotEnoughQuestions) {
+		t.Errorf("expected ErrNotEnoughQuestions, got %v", err)
+	}
+	s.mapTests[test.ID].Seed = 0
+	if _, err := s.AuditTest(test.ID); !errors.Is(err, ErrNoSeed) {
+		t.Errorf("expected ErrNoSeed, got %v", err)
+	}
+}
+
+func TestRetakeAvoidsSeenQuestionsAndKeepsBestScore(t *testing.T) {
+	s := newTestService(t, 1)
+	s.conf.RetakePolicy = config.RetakePolicy{MaxAttempts: 2}
+
+	first, err := s.GetSubjectTestForOfficer(1, 1)
+	if err != nil {
+		t.Fatalf("Failed to get test: %v", err)
+	}
+	if _, err := s.StartTest(1, first.ID); err != nil {
+		t.Fatalf("Failed to start test: %v", err)
+	}
+	answers := make(map[string]string)
+	for _, question := range first.Questions {
+		answers[fmt.Sprint(question.ID)] = question.DisplayedLetter(question.Correct)
+	}
+	if _, err := s.SubmitTest(1, first.ID, answers, ""); err != nil {
+		t.Fatalf("Failed to submit: %v", err)
+	}
+
+	second, err := s.GetSubjectTestForOfficer(1, 1)
+	if err != nil {
+		t.Fatalf("Failed to get retake: %v", err)
+	}
+	if second.ID == first.ID || second.Attempt != 2 {
+		t.Fatalf("expected a second attempt, got %s attempt %d", second.ID, second.Attempt)
+	}
+	asked := make(map[int]bool)
+	for _, question := range first.Questions {
+		asked[question.ID] = true
+	}
+	for _, question := range second.Questions {
+		if asked[question.ID] {
+			t.Errorf("question %d asked again although the bank has unseen ones", question.ID)
+		}
+	}
+	if _, err := s.StartTest(1, second.ID); err != nil {
+		t.Fatalf("Failed to start retake: %v", err)
+	}
+	if _, err := s.SubmitTest(1, second.ID, nil, ""); err != nil {
+		t.Fatalf("Failed to submit retake: %v", err)
+	}
+
+	// No attempts left: the last test is returned again
+	again, err := s.GetSubjectTestForOfficer(1, 1)
+	if err != nil || again.ID != second.ID {
+		t.Fatalf("expected the last attempt back, got %v %v", again, err)
+	}
+	if score := s.GetAllOfficers()[0].Score; score != 10 {
+		t.Errorf("expected the best score 10, got %v", score)
+	}
+	s.conf.RetakePolicy.Scoring = config.ScoringAverage
+	if score := s.GetAllOfficers()[0].Score; score != 5 {
+		t.Errorf("expected the average score 5, got %v", score)
+	}
+}
+
+func TestSubmitRejectsAnswersOutsideTheTest(t *testing.T) {
+	s := newTestService(t, 1)
+	test, err := s.GetSubjectTestForOfficer(1, 1)
+	if err != nil {
+		t.Fatalf("Failed to get test: %v", err)
+	}
+	if _, err := s.StartTest(1, test.ID); err != nil {
+		t.Fatalf("Failed to start test: %v", err)
+	}
+	first, second := fmt.Sprint(test.Questions[0].ID), fmt.Sprint(test.Questions[1].ID)
+
+	_, err = s.SubmitTest(1, test.ID, map[string]string{first: "Z", "999999": "A", second: "b"}, "")
+	var invalid *AnswerValidationError
+	if !errors.As(err, &invalid) {
+		t.Fatalf("expected an answer validation error, got %v", err)
+	}
+	reasons := make(map[string]string)
+	for _, answerErr := range invalid.Errors {
+		reasons[answerErr.QuestionID] = answerErr.Reason
+	}
+	if len(reasons) != 2 || reasons[first] != AnswerErrorInvalidOption || reasons["999999"] != AnswerErrorUnknownQuestion {
+		t.Fatalf("unexpected rejected entries: %+v", invalid.Errors)
+	}
+
+	// Nothing was recorded, a valid submission still goes through
+	submission, err := s.SubmitTest(1, test.ID, map[string]string{first: " ", second: "b"}, "")
+	if err != nil {
+		t.Fatalf("Failed to submit: %v", err)
+	}
+	if len(submission.Answers) != len(test.Questions) {
+		t.Fatalf("expected an entry per question, got %v", submission.Answers)
+	}
+	if answer, ok := submission.Answers[first]; !ok || answer != "" {
+		t.Errorf("expected question %s recorded as unanswered, got %q", first, answer)
+	}
+	if submission.Answers[second] != "B" {
+		t.Errorf("expected the answer normalized to B, got %q", submission.Answers[second])
+	}
+}
+
+func TestSavedAnswersAreGradedOnAutoSubmit(t *testing.T) {
+	s := newTestService(t, 1)
+	test, err := s.GetSubjectTestForOfficer(1, 1)
+	if err != nil {
+		t.Fatalf("Failed to get test: %v", err)
+	}
+	if _, err := s.SaveAnswers(1, test.ID, map[string]string{}, ""); !errors.Is(err, ErrTestNotStarted) {
+		t.Fatalf("expected saving before start to fail, got %v", err)
+	}
+	if _, err := s.StartTest(1, test.ID); err != nil {
+		t.Fatalf("Failed to start test: %v", err)
+	}
+
+	// Save every correct answer in two batches, then change one back to blank
+	half := len(test.Questions) / 2
+	for _, batch := range [][]*model.Question{test.Questions[:half], test.Questions[half:]} {
+		answers := make(map[string]string)
+		for _, question := range batch {
+			answers[fmt.Sprint(question.ID)] = question.DisplayedLetter(question.Correct)
+		}
+		if _, err := s.SaveAnswers(1, test.ID, answers, ""); err != nil {
+			t.Fatalf("Failed to save answers: %v", err)
+		}
+	}
+	cleared := fmt.Sprint(test.Questions[0].ID)
+	saved, err := s.SaveAnswers(1, test.ID, map[string]string{cleared: ""}, "")
+	if err != nil {
+		t.Fatalf("Failed to clear answer: %v", err)
+	}
+	if len(saved) != len(test.Questions) || saved[cleared].Answer != "" || saved[cleared].SavedAt == 0 {
+		t.Fatalf("unexpected saved answers: %+v", saved)
+	}
+
+	// A refresh gets the saved answers back
+	resumed, err := s.GetSubjectTestForOfficer(1, 1)
+	if err != nil {
+		t.Fatalf("Failed to get test again: %v", err)
+	}
+	if len(resumed.SavedAnswers) != len(test.Questions) {
+		t.Fatalf("expected saved answers with the test, got %+v", resumed.SavedAnswers)
+	}
+
+	submissions := s.autoSubmitExpired(resumed.Deadline() + 1)
+	if len(submissions) != 1 {
+		t.Fatalf("expected 1 auto-submission, got %d", len(submissions))
+	}
+	want := float32(len(test.Questions)-1) / float32(len(test.Questions)) * 10
+	if submissions[0].Score != want {
+		t.Errorf("expected the saved answers to score %v, got %v", want, submissions[0].Score)
+	}
+}
+
+func TestQuestionsOnlyForStartedTest(t *testing.T) {
+	s := newTestService(t, 1)
+	test, err := s.GetSubjectTestForOfficer(1, 1)
+	if err != nil {
+		t.Fatalf("Failed to get test: %v", err)
+	}
+	if view := model.NewCandidateTest(test); len(view.Questions) != 0 || view.NumQuestions != len(test.Questions) {
+		t.Fatalf("expected metadata only before start, got %d questions of %d", len(view.Questions), view.NumQuestions)
+	}
+	if _, err := s.GetTestQuestions(1, test.ID); !errors.Is(err, ErrTestNotStarted) {
+		t.Fatalf("expected questions to be withheld before start, got %v", err)
+	}
+	if _, err := s.StartTest(1, test.ID); err != nil {
+		t.Fatalf("Failed to start test: %v", err)
+	}
+	started, err := s.GetTestQuestions(1, test.ID)
+	if err != nil {
+		t.Fatalf("Failed to get questions: %v", err)
+	}
+	if len(started.Questions) != len(test.Questions) {
+		t.Errorf("expected %d questions, got %d", len(test.Questions), len(started.Questions))
+	}
+}
+
+func TestRemainingTimeFollowsTheServerClock(t *testing.T) {
+	s := newTestService(t, 1)
+	test, err := s.GetSubjectTestForOfficer(1, 1)
+	if err != nil {
+		t.Fatalf("Failed to get test: %v", err)
+	}
+	if test.RemainingTime != test.Duration {
+		t.Fatalf("expected the full duration before start, got %d", test.RemainingTime)
+	}
+	if _, err := s.StartTest(1, test.ID); err != nil {
+		t.Fatalf("Failed to start test: %v", err)
+	}
+	// Pretend the officer started 100 seconds ago
+	s.mapTests[test.ID].StartTime -= 100
+
+	clock, err := s.GetTestClock(1, test.ID)
+	if err != nil {
+		t.Fatalf("Failed to get clock: %v", err)
+	}
+	if want := int(clock.Deadline - clock.ServerTime); clock.RemainingTime != want || want > test.Duration-100 {
+		t.Errorf("expected %d seconds left, got %d", want, clock.RemainingTime)
+	}
+	reread, err := s.GetTestQuestions(1, test.ID)
+	if err != nil {
+		t.Fatalf("Failed to get questions: %v", err)
+	}
+	if reread.RemainingTime > test.Duration-100 {
+		t.Errorf("remaining time not recomputed on read: %d", reread.RemainingTime)
+	}
+}
+
+func TestSubmissionStatusFollowsTheGracePeriod(t *testing.T) {
+	s := newTestService(t, 3)
+	s.conf.GracePeriod = 60
+
+	// Officer 1 submits in time, officer 2 within the grace period and
+	// officer 3 after it
+	tests := make(map[int]*model.Test)
+	for officerID, elapsed := range map[int]int{1: 0, 2: 30, 3: 120} {
+		test, err := s.GetSubjectTestForOfficer(officerID, 1)
+		if err != nil {
+			t.Fatalf("Failed to get test: %v", err)
+		}
+		if _, err := s.StartTest(officerID, test.ID); err != nil {
+			t.Fatalf("Failed to start test: %v", err)
+		}
+		if officerID == 3 {
+			// Only the first answer is saved in time
+			first := test.Questions[0]
+			if _, err := s.SaveAnswers(officerID, test.ID, map[string]string{fmt.Sprint(first.ID): first.DisplayedLetter(first.Correct)}, ""); err != nil {
+				t.Fatalf("Failed to save answer: %v", err)
+			}
+		}
+		// Pretend the deadline passed the given seconds ago
+		if elapsed > 0 {
+			s.mapTests[test.ID].StartTime -= int64(test.Duration + elapsed)
+		}
+		tests[officerID] = test
+	}
+
+	// A refresh within the grace period still gets the test
+	if _, err := s.GetSubjectTestForOfficer(2, 1); err != nil {
+		t.Errorf("expected the test within the grace period, got %v", err)
+	}
+	if _, err := s.GetSubjectTestForOfficer(3, 1); !errors.Is(err, ErrTestExpired) {
+		t.Errorf("expected ErrTestExpired after the grace period, got %v", err)
+	}
+
+	want := map[int]struct {
+		status string
+		score  float32
+	}{
+		1: {model.SubmissionStatusOnTime, 10},
+		2: {model.SubmissionStatusInGrace, 10},
+		// The submitted answers are dropped, the saved one is graded
+		3: {model.SubmissionStatusLate, 10 / float32(len(tests[3].Questions))},
+	}
+	for officerID, test := range tests {
+		answers := make(map[string]string)
+		for _, question := range test.Questions {
+			answers[fmt.Sprint(question.ID)] = question.DisplayedLetter(question.Correct)
+		}
+		submission, err := s.SubmitTest(officerID, test.ID, answers, "")
+		if err != nil {
+			t.Fatalf("Failed to submit test of officer %d: %v", officerID, err)
+		}
+		if submission.Status != want[officerID].status || submission.Score != want[officerID].score {
+			t.Errorf("officer %d: expected %s with score %v, got %s with %v", officerID, want[officerID].status, want[officerID].score, submission.Status, submission.Score)
+		}
+	}
+
+	// Finished tests are returned as finished, long after their deadline too
+	s.mapTests[tests[1].ID].StartTime -= int64(tests[1].Duration + 120)
+	for officerID := range tests {
+		test, err := s.GetSubjectTestForOfficer(officerID, 1)
+		if err != nil {
+			t.Fatalf("Failed to get finished test of officer %d: %v", officerID, err)
+		}
+		if !test.IsFinished {
+			t.Errorf("expected the finished test of officer %d", officerID)
+		}
+	}
+}
+
+func TestSubmitRetryReturnsOriginalSubmission(t *testing.T) {
+	s := newTestService(t, 1)
+	test, err := s.GetSubjectTestForOfficer(1, 1)
+	if err != nil {
+		t.Fatalf("Failed to get test: %v", err)
+	}
+	if _, err := s.StartTest(1, test.ID); err != nil {
+		t.Fatalf("Failed to start test: %v", err)
+	}
+	questionID := fmt.Sprint(test.Questions[0].ID)
+
+	// A retried save is not saved again, the client sends one save at a
+	// time so only the last one is remembered
+	if _, err := s.SaveAnswers(1, test.ID, map[string]string{questionID: "A"}, "save-1"); err != nil {
+		t.Fatalf("Failed to save: %v", err)
+	}
+	if _, err := s.SaveAnswers(1, test.ID, map[string]string{questionID: "B"}, "save-2"); err != nil {
+		t.Fatalf("Failed to save: %v", err)
+	}
+	saved, err := s.SaveAnswers(1, test.ID, map[string]string{questionID: "b"}, "save-2")
+	if err != nil || saved[questionID].Answer != "B" || saved[questionID].SavedAt == 0 {
+		t.Fatalf("expected the retry to return B, got %+v %v", saved[questionID], err)
+	}
+	if _, err := s.SaveAnswers(1, test.ID, map[string]string{questionID: "C"}, "save-2"); !errors.Is(err, ErrIdempotencyKeyReused) {
+		t.Fatalf("expected a reused key to conflict, got %v", err)
+	}
+	if stored := s.mapTests[test.ID]; stored.LastSaveKey != "save-2" {
+		t.Errorf("expected only the last save key kept, got %q", stored.LastSaveKey)
+	}
+
+	answers := map[string]string{questionID: "C"}
+	original, err := s.SubmitTest(1, test.ID, answers, "submit-1")
+	if err != nil {
+		t.Fatalf("Failed to submit: %v", err)
+	}
+	retried, err := s.SubmitTest(1, test.ID, answers, "submit-1")
+	if err != nil || retried.ID != original.ID {
+		t.Fatalf("expected the original submission back, got %v %v", retried, err)
+	}
+	if _, err := s.SubmitTest(1, test.ID, map[string]string{questionID: "D"}, "submit-1"); !errors.Is(err, ErrIdempotencyKeyReused) {
+		t.Errorf("expected other answers with the same key to conflict, got %v", err)
+	}
+	if _, err := s.SubmitTest(1, test.ID, answers, "submit-2"); !errors.Is(err, ErrTestAlreadySubmitted) {
+		t.Errorf("expected a new key to be refused, got %v", err)
+	}
+	if stored := s.mapTests[test.ID]; stored.LastSaveKey != "" || stored.LastSaveHash != "" {
+		t.Errorf("expected the save key dropped once submitted, got %q", stored.LastSaveKey)
+	}
+}
+
+func TestInvalidatedTestStopsCounting(t *testing.T) {
+	s := newTestService(t, 1)
+	hash, _ := bcrypt.GenerateFromPassword([]byte("secret"), bcrypt.MinCost)
+	s.conf.Admins = []*config.AdminAccount{{Username: "proctor", PasswordHash: string(hash)}}
+	if _, err := s.AuthenticateAdmin("proctor", "wrong"); !errors.Is(err, ErrInvalidCredentials) {
+		t.Fatalf("expected a wrong password to be refused, got %v", err)
+	}
+	if username, err := s.AuthenticateAdmin("proctor", "secret"); err != nil || username != "proctor" {
+		t.Fatalf("Failed to authenticate admin: %v", err)
+	}
+
+	test, err := s.GetSubjectTestForOfficer(1, 1)
+	if err != nil {
+		t.Fatalf("Failed to get test: %v", err)
+	}
+	if _, err := s.StartTest(1, test.ID); err != nil {
+		t.Fatalf("Failed to start test: %v", err)
+	}
+	if progress := s.ListInProgressTests(); len(progress) != 1 || progress[0].TestID != test.ID {
+		t.Fatalf("expected the started test in progress, got %+v", progress)
+	}
+	answers := make(map[string]string)
+	for _, question := range test.Questions {
+		answers[fmt.Sprint(question.ID)] = question.DisplayedLetter(question.Correct)
+	}
+	if _, err := s.SubmitTest(1, test.ID, answers, ""); err != nil {
+		t.Fatalf("Failed to submit: %v", err)
+	}
+
+	invalidated, err := s.InvalidateTest(test.ID, "copied answers", "proctor")
+	if err != nil {
+		t.Fatalf("Failed to invalidate: %v", err)
+	}
+	if invalidated.InvalidatedBy != "proctor" || invalidated.InvalidReason != "copied answers" {
+		t.Errorf("invalidation not recorded: %+v", invalidated)
+	}
+	if _, err := s.InvalidateTest(test.ID, "again", "proctor"); !errors.Is(err, ErrTestInvalidated) {
+		t.Errorf("expected a second invalidation to fail, got %v", err)
+	}
+	submissions, _ := s.GetOfficerSubmissions(1)
+	if len(submissions) != 1 || !submissions[0].Invalidated {
+		t.Fatalf("expected the submission marked invalidated, got %+v", submissions)
+	}
+	if score := s.GetAllOfficers()[0].Score; score != 0 {
+		t.Errorf("expected an invalidated submission not to count, got %v", score)
+	}
+}
+
+func TestAdminActionsOnACrashedTest(t *testing.T) {
+	s := newTestService(t, 1)
+	test, err := s.GetSubjectTestForOfficer(1, 1)
+	if err != nil {
+		t.Fatalf("Failed to get test: %v", err)
+	}
+	if _, err := s.StartTest(1, test.ID); err != nil {
+		t.Fatalf("Failed to start test: %v", err)
+	}
+
+	extended, err := s.ExtendTest(test.ID, 10, "slow PC", "proctor")
+	if err != nil {
+		t.Fatalf("Failed to extend: %v", err)
+	}
+	if extended.Duration != test.Duration+600 {
+		t.Errorf("expected 600 more seconds, got duration %d", extended.Duration)
+	}
+
+	paused, err := s.PauseTest(test.ID, "PC crashed", "proctor")
+	if err != nil {
+		t.Fatalf("Failed to pause: %v", err)
+	}
+	if _, err := s.SubmitTest(1, test.ID, nil, ""); !errors.Is(err, ErrTestPaused) {
+		t.Errorf("expected submitting a paused test to fail, got %v", err)
+	}
+	// Pretend the pause started 100 seconds ago
+	_, unlock, _ := s.lockOfficer(1)
+	stored := s.findTest(1, test.ID)
+	stored.PausedAt -= 100
+	remaining := stored.Remaining(time.Now().Unix())
+	unlock()
+	if remaining != paused.RemainingTime+100 {
+		t.Errorf("expected the remaining time frozen at the pause, got %d for %d", remaining, paused.RemainingTime)
+	}
+	resumed, err := s.ResumeTest(test.ID, "", "proctor")
+	if err != nil {
+		t.Fatalf("Failed to resume: %v", err)
+	}
+	if resumed.PausedFor < 100 || resumed.Deadline() != extended.Deadline()+int64(resumed.PausedFor) {
+		t.Errorf("expected the deadline pushed back by the pause, got %+v", resumed)
+	}
+	if _, err := s.ResumeTest(test.ID, "", "proctor"); !errors.Is(err, ErrTestNotPaused) {
+		t.Errorf("expected resuming a running test to fail, got %v", err)
+	}
+
+	if _, err := s.ResetTest(test.ID, "PC replaced", "proctor"); err != nil {
+		t.Fatalf("Failed to reset: %v", err)
+	}
+	fresh, err := s.GetSubjectTestForOfficer(1, 1)
+	if err != nil {
+		t.Fatalf("Failed to get a fresh test: %v", err)
+	}
+	if fresh.ID == test.ID || fresh.Attempt != 1 || fresh.StartTime != 0 {
+		t.Errorf("expected a fresh first attempt after the reset, got %+v", fresh)
+	}
+	if _, err := s.StartTest(1, test.ID); !errors.Is(err, ErrTestNotFound) {
+		t.Errorf("expected the reset test to be gone, got %v", err)
+	}
+
+	if _, err := s.StartTest(1, fresh.ID); err != nil {
+		t.Fatalf("Failed to start fresh test: %v", err)
+	}
+	answers := make(map[string]string)
+	for _, question := range fresh.Questions {
+		answers[fmt.Sprint(question.ID)] = question.DisplayedLetter(question.Correct)
+	}
+	submission, err := s.SubmitTest(1, fresh.ID, answers, "")
+	if err != nil {
+		t.Fatalf("Failed to submit: %v", err)
+	}
+	voided, err := s.VoidSubmission(submission.ID, "wrong officer", "proctor")
+	if err != nil {
+		t.Fatalf("Failed to void: %v", err)
+	}
+	if voided.VoidedBy != "proctor" || voided.VoidReason != "wrong officer" || submission.VoidedAt != 0 {
+		t.Errorf("void not recorded on a new copy: %+v", voided)
+	}
+	if _, err := s.VoidSubmission(submission.ID, "again", "proctor"); !errors.Is(err, ErrSubmissionVoided) {
+		t.Errorf("expected a second void to fail, got %v", err)
+	}
+	if score := s.GetAllOfficers()[0].Score; score != 0 {
+		t.Errorf("expected a voided submission not to count, got %v", score)
+	}
+
+	actions, err := s.ListAdminActions(1, "")
+	if err != nil {
+		t.Fatalf("Failed to list the audit trail: %v", err)
+	}
+	counts := make(map[string]int)
+	for _, action := range actions {
+		counts[action.Action]++
+	}
+	for _, action := range []string{model.AdminActionExtend, model.AdminActionPause, model.AdminActionResume, model.AdminActionReset, model.AdminActionVoid} {
+		if counts[action] != 1 {
+			t.Errorf("expected one %s in the audit trail, got %+v", action, actions)
+		}
+	}
+	if forTest, _ := s.ListAdminActions(0, test.ID); len(forTest) != 4 {
+		t.Errorf("expected 4 actions on the crashed test, got %d", len(forTest))
+	}
+}
+
+func TestResetAfterAutoSubmitGivesTheAttemptBack(t *testing.T) {
+	s := newTestService(t, 1)
+	test, err := s.GetSubjectTestForOfficer(1, 1)
+	if err != nil {
+		t.Fatalf("Failed to get test: %v", err)
+	}
+	started, err := s.StartTest(1, test.ID)
+	if err != nil {
+		t.Fatalf("Failed to start test: %v", err)
+	}
+
+	// The PC stays down past the deadline and the server auto-submits
+	submissions := s.autoSubmitExpired(started.Deadline() + int64(s.conf.GracePeriod) + 1)
+	if len(submissions) != 1 || submissions[0].Status != model.SubmissionStatusAuto {
+		t.Fatalf("expected 1 auto-submission, got %+v", submissions)
+	}
+	if _, err := s.ResetTest(test.ID, "PC crashed", "proctor"); err != nil {
+		t.Fatalf("Failed to reset the auto-submitted test: %v", err)
+	}
+	officer, err := s.GetOfficerByID(1)
+	if err != nil {
+		t.Fatalf("Failed to get officer: %v", err)
+	}
+	if len(officer.ListSubmission) != 1 || officer.ListSubmission[0].VoidedBy != "proctor" || submissions[0].VoidedAt != 0 {
+		t.Errorf("expected the auto-submission voided on a new copy, got %+v", officer.ListSubmission)
+	}
+
+	fresh, err := s.GetSubjectTestForOfficer(1, 1)
+	if err != nil {
+		t.Fatalf("Failed to get a fresh test: %v", err)
+	}
+	if fresh.ID == test.ID || fresh.Attempt != 1 || fresh.IsFinished {
+		t.Fatalf("expected a fresh first attempt after the reset, got %+v", fresh)
+	}
+
+	// A test the officer submitted cannot be reset
+	if _, err := s.StartTest(1, fresh.ID); err != nil {
+		t.Fatalf("Failed to start fresh test: %v", err)
+	}
+	if _, err := s.SubmitTest(1, fresh.ID, map[string]string{fmt.Sprint(fresh.Questions[0].ID): "A"}, ""); err != nil {
+		t.Fatalf("Failed to submit: %v", err)
+	}
+	if _, err := s.ResetTest(fresh.ID, "", "proctor"); !errors.Is(err, ErrTestAlreadySubmitted) {
+		t.Errorf("expected a submitted test to stay, got %v", err)
+	}
+}
+
+func TestAccommodationsExtendNewTests(t *testing.T) {
+	s := newTestService(t, 2)
+	s.conf.AccommodationPath = filepath.Join(t.TempDir(), "accommodations.json")
+	if _, err := s.SetAccommodation(&model.Accommodation{OfficerID: 1, ExtraMinutes: 5}, "proctor"); err != nil {
+		t.Fatalf("Failed to set accommodation: %v", err)
+	}
+	// The one for the subject takes precedence over the one for every subject
+	if _, err := s.SetAccommodation(&model.Accommodation{OfficerID: 1, SubjectID: 1, ExtraMinutes: 10, ExtraPercent: 25}, "proctor"); err != nil {
+		t.Fatalf("Failed to set accommodation: %v", err)
+	}
+	if _, err := s.SetAccommodation(&model.Accommodation{OfficerID: 1, SubjectID: 9, ExtraMinutes: 10}, "proctor"); !errors.Is(err, ErrSubjectNotFound) {
+		t.Errorf("expected an unknown subject to be refused, got %v", err)
+	}
+
+	accommodated, err := s.GetSubjectTestForOfficer(1, 1)
+	if err != nil {
+		t.Fatalf("Failed to get test: %v", err)
+	}
+	if accommodated.ExtraTime != 600+450 || accommodated.Duration != 1800+600+450 || accommodated.RemainingTime != accommodated.Duration {
+		t.Errorf("expected 10 minutes and 25%% extra on 30 minutes, got duration %d with extra %d", accommodated.Duration, accommodated.ExtraTime)
+	}
+	regular, err := s.GetSubjectTestForOfficer(2, 1)
+	if err != nil {
+		t.Fatalf("Failed to get test: %v", err)
+	}
+	if regular.ExtraTime != 0 || regular.Duration != 1800 {
+		t.Errorf("expected no extra time for officer 2, got duration %d with extra %d", regular.Duration, regular.ExtraTime)
+	}
+
+	// The file written through the API is read back at startup
+	if err := s.DeleteAccommodation(1, 0, "proctor"); err != nil {
+		t.Fatalf("Failed to delete accommodation: %v", err)
+	}
+	if err := s.DeleteAccommodation(1, 0, "proctor"); !errors.Is(err, ErrAccommodationNotFound) {
+		t.Errorf("expected a second delete to fail, got %v", err)
+	}
+	if err := s.loadAccommodations(); err != nil {
+		t.Fatalf("Failed to reload accommodations: %v", err)
+	}
+	if list := s.ListAccommodations(); len(list) != 1 || list[0].SubjectID != 1 || list[0].ExtraPercent != 25 {
+		t.Errorf("expected the subject accommodation to survive a reload, got %+v", list)
+	}
+}
+
+func TestStoredTestsMoveToStableQuestionIDs(t *testing.T) {
+	conf := &config.AppConfig{
+		DataPath:    filepath.Join(t.TempDir(), "contest.db"),
+		ListOfficer: []*model.Officer{{ID: 1, Name: "Cán bộ 1", PIN: "1234"}},
+	}
+
+	// A test stored while questions were numbered in folder order, with a
+	// question since removed from the bank
+	st, err := store.Open(conf.DataPath)
+	if err != nil {
+		t.Fatalf("Failed to open store: %v", err)
+	}
+	test := &model.Test{
+		ID:         "01J9Z3K6T0Q8WJ5V2N4C7B1M9X",
+		Subject:    &model.Subject{ID: 1, Name: "Điều lệnh"},
+		Officer:    &model.Officer{ID: 1},
+		IsFinished: true,
+		Attempt:    1,
+		Questions: []*model.Question{
+			{ID: 3, Content: "Câu 1.3", AnswerA: "Phương án A", AnswerB: "Phương án B", Correct: "D"},
+			{ID: 0, Content: "Câu   2.0 ", AnswerA: "Phương án A", AnswerB: "Phương án B", Correct: "A"},
+			{ID: 7, Content: "Câu đã xóa", AnswerA: "Có", AnswerB: "Không", Correct: "B"},
+		},
+	}
+	answers := map[string]string{"3": "D", "0": "B", "7": "B"}
+	submission := &model.Submission{ID: "01J9Z3M2A7H5R0D3K8P6F4G2YT", OfficerID: 1, TestID: test.ID, SubjectID: 1, Answers: answers, CanonicalAnswers: answers, Score: 6.6}
+	if err := st.SaveSubmission(test, submission); err != nil {
+		t.Fatalf("Failed to save submission: %v", err)
+	}
+	st.Close()
+
+	// Migrated on the first start only
+	for restart := 0; restart < 2; restart++ {
+		s := openTestService(t, conf)
+		restored := s.findTest(1, test.ID)
+		var ids []int
+		for _, question := range restored.Questions {
+			ids = append(ids, question.ID)
+		}
+		if fmt.Sprint(ids) != "[103 200 -8]" {
+			t.Errorf("restart %d: expected the bank IDs and a negative one for the removed question, got %v", restart, ids)
+		}
+		got := s.GetAllOfficers()[0].ListSubmission[0]
+		if fmt.Sprint(got.Answers) != "map[-8:B 103:D 200:B]" || fmt.Sprint(got.CanonicalAnswers) != "map[-8:B 103:D 200:B]" {
+			t.Errorf("restart %d: expected the answers keyed by the new IDs, got %v and %v", restart, got.Answers, got.CanonicalAnswers)
+		}
+		s.Close()
+	}
+}
@@ -0,0 +1,217 @@
+package store
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/lehaisonagentai3/free-contest/backend/internal/model"
+	bolt "go.etcd.io/bbolt"
+)
+
+var (
+	bucketTests       = []byte("tests")
+	bucketSubmissions = []byte("submissions")
+	bucketActions     = []byte("admin_actions")
+	bucketMigrations  = []byte("migrations")
+)
+
+// Store persists generated tests and submissions in an embedded bbolt file
+type Store struct {
+	db *bolt.DB
+}
+
+func Open(path string) (*Store, error) {
+	// The file is locked while open, a second server on it gives up
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: time.Second})
+	if errors.Is(err, bolt.ErrTimeout) {
+		return nil, fmt.Errorf("open data file %s: it is in use by another server", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("open data file %s: %w", path, err)
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, name := range [][]byte{bucketTests, bucketSubmissions, bucketActions, bucketMigrations} {
+			if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &Store{db: db}, nil
+}
+
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// SaveTest writes the test, keyed by its ID. Only the officer ID is kept,
+// the full officer is re-attached when the test is loaded.
+func (s *Store) SaveTest(test *model.Test) error {
+	data, err := json.Marshal(testRecord(test))
+	if err != nil {
+		return err
+	}
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucketTests).Put([]byte(test.ID), data)
+	})
+}
+
+// SaveSubmission writes the submission together with its finished test in a
+// single transaction, so a crash can never leave one without the other.
+func (s *Store) SaveSubmission(test *model.Test, submission *model.Submission) error {
+	testData, err := json.Marshal(testRecord(test))
+	if err != nil {
+		return err
+	}
+	submissionData, err := json.Marshal(submission)
+	if err != nil {
+		return err
+	}
+	return s.db.Update(func(tx *bolt.Tx) error {
+		if err := tx.Bucket(bucketTests).Put([]byte(test.ID), testData); err != nil {
+			return err
+		}
+		return tx.Bucket(bucketSubmissions).Put([]byte(submission.ID), submissionData)
+	})
+}
+
+// SaveAdminAction writes an admin action together with the test and the
+// submission it changed, either of which may be nil, in a single transaction
+// so the audit trail never misses a change.
+func (s *Store) SaveAdminAction(action *model.AdminAction, test *model.Test, submission *model.Submission) error {
+	actionData, err := json.Marshal(action)
+	if err != nil {
+		return err
+	}
+	var testData, submissionData []byte
+	if test != nil {
+		if testData, err = json.Marshal(testRecord(test)); err != nil {
+			return err
+		}
+	}
+	if submission != nil {
+		if submissionData, err = json.Marshal(submission); err != nil {
+			return err
+		}
+	}
+	return s.db.Update(func(tx *bolt.Tx) error {
+		if test != nil {
+			if err := tx.Bucket(bucketTests).Put([]byte(test.ID), testData); err != nil {
+				return err
+			}
+		}
+		if submission != nil {
+			if err := tx.Bucket(bucketSubmissions).Put([]byte(submission.ID), submissionData); err != nil {
+				return err
+			}
+		}
+		return tx.Bucket(bucketActions).Put([]byte(action.ID), actionData)
+	})
+}
+
+// LoadTests returns every stored test
+func (s *Store) LoadTests() ([]*model.Test, error) {
+	var tests []*model.Test
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucketTests).ForEach(func(k, v []byte) error {
+			test := &model.Test{}
+			if err := json.Unmarshal(v, test); err != nil {
+				return fmt.Errorf("decode test %s: %w", k, err)
+			}
+			tests = append(tests, test)
+			return nil
+		})
+	})
+	return tests, err
+}
+
+// LoadSubmissions returns every stored submission ordered by submission time
+func (s *Store) LoadSubmissions() ([]*model.Submission, error) {
+	var submissions []*model.Submission
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucketSubmissions).ForEach(func(k, v []byte) error {
+			submission := &model.Submission{}
+			if err := json.Unmarshal(v, submission); err != nil {
+				return fmt.Errorf("decode submission %s: %w", k, err)
+			}
+			submissions = append(submissions, submission)
+			return nil
+		})
+	})
+	sort.SliceStable(submissions, func(i, j int) bool {
+		return submissions[i].SubmittedAt < submissions[j].SubmittedAt
+	})
+	return submissions, err
+}
+
+// LoadAdminActions returns the audit trail, oldest first
+func (s *Store) LoadAdminActions() ([]*model.AdminAction, error) {
+	var actions []*model.AdminAction
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucketActions).ForEach(func(k, v []byte) error {
+			action := &model.AdminAction{}
+			if err := json.Unmarshal(v, action); err != nil {
+				return fmt.Errorf("decode admin action %s: %w", k, err)
+			}
+			actions = append(actions, action)
+			return nil
+		})
+	})
+	sort.SliceStable(actions, func(i, j int) bool {
+		return actions[i].At < actions[j].At
+	})
+	return actions, err
+}
+
+// Migrated reports whether the named migration was applied to the data file
+func (s *Store) Migrated(name string) (bool, error) {
+	var done bool
+	err := s.db.View(func(tx *bolt.Tx) error {
+		done = tx.Bucket(bucketMigrations).Get([]byte(name)) != nil
+		return nil
+	})
+	return done, err
+}
+
+// SaveMigration writes the tests and submissions rewritten by a migration and
+// marks it applied at the given time, in a single transaction so a crash
+// never leaves the data half migrated.
+func (s *Store) SaveMigration(name string, at int64, tests []*model.Test, submissions []*model.Submission) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		for _, test := range tests {
+			data, err := json.Marshal(testRecord(test))
+			if err != nil {
+				return err
+			}
+			if err := tx.Bucket(bucketTests).Put([]byte(test.ID), data); err != nil {
+				return err
+			}
+		}
+		for _, submission := range submissions {
+			data, err := json.Marshal(submission)
+			if err != nil {
+				return err
+			}
+			if err := tx.Bucket(bucketSubmissions).Put([]byte(submission.ID), data); err != nil {
+				return err
+			}
+		}
+		return tx.Bucket(bucketMigrations).Put([]byte(name), []byte(strconv.FormatInt(at, 10)))
+	})
+}
+
+func testRecord(test *model.Test) *model.Test {
+	record := *test
+	if test.Officer != nil {
+		record.Officer = &model.Officer{ID: test.Officer.ID}
+	}
+	return &record
+}
@@ -0,0 +1,82 @@
+package store
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/lehaisonagentai3/free-contest/backend/internal/model"
+)
+
+func TestStoreReopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "contest.db")
+	st, err := Open(path)
+	if err != nil {
+		t.Fatalf("Failed to open store: %v", err)
+	}
+
+	test := &model.Test{
+		ID:        "01J9Z3K6T0Q8WJ5V2N4C7B1M9X",
+		Duration:  600,
+		StartTime: 1700000000,
+		Subject:   &model.Subject{ID: 1, Name: "Điều lệnh"},
+		Officer:   &model.Officer{ID: 3, Name: "Lê Văn C", ListSubmission: []*model.Submission{{ID: "01J9Z3J0000000000000000000"}}},
+		Questions: []*model.Question{{ID: 10, Content: "Câu 1", Correct: "B"}},
+	}
+	if err := st.SaveTest(test); err != nil {
+		t.Fatalf("Failed to save test: %v", err)
+	}
+	test.IsFinished = true
+	submission := &model.Submission{ID: "01J9Z3M2A7H5R0D3K8P6F4G2YT", OfficerID: 3, TestID: test.ID, Score: 10, SubmittedAt: 1700000300}
+	if err := st.SaveSubmission(test, submission); err != nil {
+		t.Fatalf("Failed to save submission: %v", err)
+	}
+	if err := st.Close(); err != nil {
+		t.Fatalf("Failed to close store: %v", err)
+	}
+
+	st, err = Open(path)
+	if err != nil {
+		t.Fatalf("Failed to reopen store: %v", err)
+	}
+	defer st.Close()
+
+	tests, err := st.LoadTests()
+	if err != nil {
+		t.Fatalf("Failed to load tests: %v", err)
+	}
+	if len(tests) != 1 {
+		t.Fatalf("expected 1 test, got %d", len(tests))
+	}
+	got := tests[0]
+	if !got.IsFinished || got.StartTime != test.StartTime || got.Subject.ID != 1 {
+		t.Errorf("test not restored intact: %+v", got)
+	}
+	if got.Officer == nil || got.Officer.ID != 3 || got.Officer.ListSubmission != nil {
+		t.Errorf("expected officer reference only, got %+v", got.Officer)
+	}
+	if len(got.Questions) != 1 || got.Questions[0].Correct != "B" {
+		t.Errorf("questions not restored: %+v", got.Questions)
+	}
+
+	submissions, err := st.LoadSubmissions()
+	if err != nil {
+		t.Fatalf("Failed to load submissions: %v", err)
+	}
+	if len(submissions) != 1 || submissions[0].Score != 10 || submissions[0].TestID != test.ID {
+		t.Errorf("submission not restored: %+v", submissions)
+	}
+}
+
+func TestOpenRefusesFileInUse(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "contest.db")
+	st, err := Open(path)
+	if err != nil {
+		t.Fatalf("Failed to open store: %v", err)
+	}
+	defer st.Close()
+
+	if second, err := Open(path); err == nil {
+		second.Close()
+		t.Fatal("expected the second open of the data file to fail")
+	}
+}
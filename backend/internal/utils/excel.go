@@ -0,0 +1,292 @@
+package utils
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/lehaisonagentai3/free-contest/backend/internal/model"
+	"github.com/xuri/excelize/v2"
+)
+
+// Layouts of a question sheet, detected per sheet
+const (
+	// LayoutBlocks is a "Câu N" row with the question, one row per option
+	// labelled A to D, a "Đáp án" row with the correct letter and optionally
+	// a "Mã" row with the ID. Blank rows anywhere are ignored.
+	LayoutBlocks = "blocks"
+	// LayoutTable is a header row naming the columns (Content, A, B, C, D,
+	// Correct and optionally ID) followed by one question per row
+	LayoutTable = "table"
+)
+
+// SheetQuestion is a question read from a sheet with the row it starts on
+type SheetQuestion struct {
+	Row      int    // 1-based, as shown by Excel
+	ID       string // from the ID column or the "Mã" row, "" when not given
+	Question *model.Question
+}
+
+// QuestionSheet is the questions read from one sheet of a workbook
+type QuestionSheet struct {
+	Name      string
+	Layout    string
+	Questions []*SheetQuestion
+}
+
+var (
+	// "Câu", "Câu 1", "Câu 1:", "câu 12." optionally followed by the question
+	// when the cells are merged
+	questionLabel = regexp.MustCompile(`(?i)^câu(?:\s*\d+\s*[:.)]?\s*(.*))?$`)
+	// "A" alone, the option text being in the next cell
+	optionLabel = regexp.MustCompile(`^([A-Da-d])\s*[.):]?$`)
+	// "A. text" or "A) text" in a single cell
+	inlineOption = regexp.MustCompile(`^([A-Da-d])\s*[.):]\s*(.+)$`)
+	// "Đáp án", "Đáp án:" or "Đáp án: B"
+	answerLabel = regexp.MustCompile(`(?i)^đáp\s*án(?:\s+đúng)?\s*[:.]?\s*(.*)$`)
+	// "Mã", "Mã câu hỏi: 1024" or "ID 1024", the stable ID of the question
+	idLabel = regexp.MustCompile(`(?i)^(?:mã(?:\s+câu\s+hỏi)?|id)\s*[:.]?\s*(\d*)$`)
+)
+
+// Column headers of the table layout, compared after normalizeHeader
+var tableHeaders = map[string]string{
+	"content":           "content",
+	"question":          "content",
+	"câu hỏi":           "content",
+	"nội dung":          "content",
+	"nội dung câu hỏi":  "content",
+	"a":                 "A",
+	"answer a":          "A",
+	"option a":          "A",
+	"phương án a":       "A",
+	"đáp án a":          "A",
+	"b":                 "B",
+	"answer b":          "B",
+	"option b":          "B",
+	"phương án b":       "B",
+	"đáp án b":          "B",
+	"c":                 "C",
+	"answer c":          "C",
+	"option c":          "C",
+	"phương án c":       "C",
+	"đáp án c":          "C",
+	"d":                 "D",
+	"answer d":          "D",
+	"option d":          "D",
+	"phương án d":       "D",
+	"đáp án d":          "D",
+	"correct":           "correct",
+	"answer":            "correct",
+	"correct answer":    "correct",
+	"đáp án":            "correct",
+	"đáp án đúng":       "correct",
+	"phương án đúng":    "correct",
+	"correct option":    "correct",
+	"correct (a/b/c/d)": "correct",
+	"id":                "id",
+	"question id":       "id",
+	"mã":                "id",
+	"mã câu hỏi":        "id",
+}
+
+// headerScanRows is how many rows from the top are searched for the header
+// of the table layout
+const headerScanRows = 10
+
+// LoadQuestionFromExcel reads the questions of every sheet of a workbook, see
+// ParseQuestionWorkbook
+func LoadQuestionFromExcel(filePath string) ([]*model.Question, error) {
+	sheets, err := ParseQuestionWorkbook(filePath)
+	if err != nil {
+		return nil, err
+	}
+	var questions []*model.Question
+	for _, sheet := range sheets {
+		for _, sheetQuestion := range sheet.Questions {
+			questions = append(questions, sheetQuestion.Question)
+		}
+	}
+	return questions, nil
+}
+
+// ParseQuestionWorkbook reads every sheet of a workbook, detecting for each
+// whether it uses the table or the block layout. Sheets without questions,
+// such as instructions, are left out. The questions are returned as written;
+// checking them is up to the caller.
+func ParseQuestionWorkbook(filePath string) ([]*QuestionSheet, error) {
+	f, err := excelize.OpenFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var sheets []*QuestionSheet
+	for _, name := range f.GetSheetList() {
+		rows, err := f.GetRows(name)
+		if err != nil {
+			return nil, err
+		}
+		sheet := parseQuestionSheet(name, rows)
+		if len(sheet.Questions) > 0 {
+			sheets = append(sheets, sheet)
+		}
+	}
+	return sheets, nil
+}
+
+func parseQuestionSheet(name string, rows [][]string) *QuestionSheet {
+	for i := 0; i < len(rows) && i < headerScanRows; i++ {
+		if columns := tableColumns(rows[i]); columns != nil {
+			return &QuestionSheet{Name: name, Layout: LayoutTable, Questions: parseTableRows(rows, i, columns)}
+		}
+	}
+	return &QuestionSheet{Name: name, Layout: LayoutBlocks, Questions: parseBlockRows(rows)}
+}
+
+// tableColumns returns the column of each field if the row is a header of
+// the table layout, nil otherwise
+func tableColumns(row []string) map[string]int {
+	columns := make(map[string]int)
+	for i, cell := range row {
+		if field, ok := tableHeaders[normalizeHeader(cell)]; ok {
+			if _, seen := columns[field]; !seen {
+				columns[field] = i
+			}
+		}
+	}
+	for _, field := range []string{"content", "A", "B", "C", "D", "correct"} {
+		if _, ok := columns[field]; !ok {
+			return nil
+		}
+	}
+	return columns
+}
+
+func normalizeHeader(cell string) string {
+	return strings.Join(strings.Fields(strings.ToLower(strings.TrimRight(strings.TrimSpace(cell), ":"))), " ")
+}
+
+func parseTableRows(rows [][]string, header int, columns map[string]int) []*SheetQuestion {
+	cell := func(row []string, field string) string {
+		if i := columns[field]; i < len(row) {
+			return strings.TrimSpace(row[i])
+		}
+		return ""
+	}
+	var questions []*SheetQuestion
+	for i := header + 1; i < len(rows); i++ {
+		row := rows[i]
+		if isBlankRow(row) {
+			continue
+		}
+		id := ""
+		if _, ok := columns["id"]; ok {
+			id = cell(row, "id")
+		}
+		questions = append(questions, &SheetQuestion{
+			Row: i + 1,
+			ID:  id,
+			Question: &model.Question{
+				Content: cell(row, "content"),
+				AnswerA: cell(row, "A"),
+				AnswerB: cell(row, "B"),
+				AnswerC: cell(row, "C"),
+				AnswerD: cell(row, "D"),
+				Correct: normalizeCorrect(cell(row, "correct")),
+			},
+		})
+	}
+	return questions
+}
+
+// parseBlockRows reads the block layout by the label in the first non-blank
+// cell of each row, so extra blank rows, missing spacer rows and labels
+// merged with their text do not matter. Unlabelled rows before the first
+// option continue the question text.
+func parseBlockRows(rows [][]string) []*SheetQuestion {
+	var questions []*SheetQuestion
+	var current *model.Question
+	hasOption := false
+	for i, row := range rows {
+		cells := nonBlankCells(row)
+		if len(cells) == 0 {
+			continue
+		}
+		label, rest := cells[0], strings.Join(cells[1:], " ")
+
+		if match := questionLabel.FindStringSubmatch(label); match != nil {
+			content := rest
+			if content == "" {
+				content = strings.TrimSpace(match[1])
+			}
+			current = &model.Question{Content: content}
+			hasOption = false
+			questions = append(questions, &SheetQuestion{Row: i + 1, Question: current})
+			continue
+		}
+		if current == nil {
+			continue
+		}
+
+		letter, text := "", ""
+		if match := optionLabel.FindStringSubmatch(label); match != nil {
+			letter, text = match[1], rest
+		} else if match := inlineOption.FindStringSubmatch(label); match != nil && rest == "" {
+			letter, text = match[1], strings.TrimSpace(match[2])
+		}
+		if letter != "" {
+			switch strings.ToUpper(letter) {
+			case "A":
+				current.AnswerA = text
+			case "B":
+				current.AnswerB = text
+			case "C":
+				current.AnswerC = text
+			case "D":
+				current.AnswerD = text
+			}
+			hasOption = true
+			continue
+		}
+		if match := answerLabel.FindStringSubmatch(label); match != nil {
+			answer := rest
+			if answer == "" {
+				answer = match[1]
+			}
+			current.Correct = normalizeCorrect(answer)
+			continue
+		}
+		if match := idLabel.FindStringSubmatch(label); match != nil {
+			id := rest
+			if id == "" {
+				id = match[1]
+			}
+			questions[len(questions)-1].ID = id
+			continue
+		}
+		if !hasOption {
+			current.Content = strings.TrimSpace(current.Content + "\n" + strings.Join(cells, " "))
+		}
+	}
+	return questions
+}
+
+// normalizeCorrect turns "b", " B. " or "B)" into "B". Anything else is kept
+// as written for validation to report.
+func normalizeCorrect(answer string) string {
+	answer = strings.ToUpper(strings.TrimSpace(answer))
+	return strings.TrimSpace(strings.TrimRight(answer, ".):"))
+}
+
+func nonBlankCells(row []string) []string {
+	var cells []string
+	for _, cell := range row {
+		if cell = strings.TrimSpace(cell); cell != "" {
+			cells = append(cells, cell)
+		}
+	}
+	return cells
+}
+
+func isBlankRow(row []string) bool {
+	return len(nonBlankCells(row)) == 0
+}
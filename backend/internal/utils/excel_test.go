@@ -0,0 +1,92 @@
+package utils
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/xuri/excelize/v2"
+)
+
+func TestParseQuestionWorkbookLayouts(t *testing.T) {
+	f := excelize.NewFile()
+	defer f.Close()
+	f.SetSheetName("Sheet1", "Hướng dẫn")
+	f.SetSheetRow("Hướng dẫn", "A1", &[]any{"Mỗi câu hỏi gồm 4 phương án"})
+
+	// Blocks with an extra blank line, no spacer row and labels merged with
+	// their text
+	f.NewSheet("Chương 1")
+	blocks := [][]any{
+		{"Câu 1", "Thủ đô của Việt Nam là?"},
+		{},
+		{},
+		{"A", "Hà Nội"},
+		{"B", "Huế"},
+		{"C", "Đà Nẵng"},
+		{"D", "Cần Thơ"},
+		{"Đáp án", "a"},
+		{"Câu 2: Sông dài nhất Việt Nam là?"},
+		{"A. Sông Hồng"},
+		{"B. Sông Mekong"},
+		{"C. Sông Đà"},
+		{"D. Sông Cửu Long"},
+		{"Đáp án: B."},
+	}
+	for i, row := range blocks {
+		cell, _ := excelize.CoordinatesToCellName(1, i+1)
+		f.SetSheetRow("Chương 1", cell, &row)
+	}
+
+	// One question per row under a header below a title
+	f.NewSheet("Bảng")
+	table := [][]any{
+		{"Ngân hàng câu hỏi"},
+		{"STT", "Nội dung câu hỏi", "Phương án A", "Phương án B", "Phương án C", "Phương án D", "Đáp án đúng"},
+		{1, "1 + 1 = ?", "1", "2", "3", "4", "B"},
+		{},
+		{2, "2 + 2 = ?", "4", "5", "6", "7", "A"},
+	}
+	for i, row := range table {
+		cell, _ := excelize.CoordinatesToCellName(1, i+1)
+		f.SetSheetRow("Bảng", cell, &row)
+	}
+	path := filepath.Join(t.TempDir(), "questions.xlsx")
+	if err := f.SaveAs(path); err != nil {
+		t.Fatalf("Failed to write workbook: %v", err)
+	}
+
+	sheets, err := ParseQuestionWorkbook(path)
+	if err != nil {
+		t.Fatalf("Failed to parse workbook: %v", err)
+	}
+	if len(sheets) != 2 || sheets[0].Layout != LayoutBlocks || sheets[1].Layout != LayoutTable {
+		t.Fatalf("expected a block sheet and a table sheet, got %+v", sheets)
+	}
+	first, second := sheets[0].Questions[0], sheets[0].Questions[1]
+	if len(sheets[0].Questions) != 2 || first.Row != 1 || second.Row != 9 {
+		t.Fatalf("unexpected block questions %+v", sheets[0].Questions)
+	}
+	if first.Question.AnswerD != "Cần Thơ" || first.Question.Correct != "A" {
+		t.Errorf("unexpected first question %+v", first.Question)
+	}
+	if second.Question.Content != "Sông dài nhất Việt Nam là?" || second.Question.AnswerB != "Sông Mekong" || second.Question.Correct != "B" {
+		t.Errorf("unexpected merged question %+v", second.Question)
+	}
+	rows := sheets[1].Questions
+	if len(rows) != 2 || rows[1].Row != 5 || rows[1].Question.Content != "2 + 2 = ?" || rows[1].Question.AnswerA != "4" || rows[1].Question.Correct != "A" {
+		t.Errorf("unexpected table questions %+v", rows)
+	}
+}
+
+func TestLoadQuestionFromExcelSampleBank(t *testing.T) {
+	questions, err := LoadQuestionFromExcel(filepath.Join("..", "..", "tools", "load-question-xslx", "Ngan_hang_cau_hoi.xlsx"))
+	if err != nil {
+		t.Fatalf("Failed to load the sample bank: %v", err)
+	}
+	if len(questions) != 10 {
+		t.Fatalf("expected 10 questions, got %d", len(questions))
+	}
+	if q := questions[1]; q.Content != "Sông dài nhất Việt Nam là?" || q.AnswerD != "Sông Cửu Long" || q.Correct != "B" {
+		t.Errorf("unexpected second question %+v", q)
+	}
+}
@@ -0,0 +1,31 @@
+package utils
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"time"
+)
+
+// crockford is the Crockford base32 alphabet used by ULIDs
+const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// NewID returns a 26 character ULID: a millisecond timestamp followed by 80
+// random bits. IDs sort by creation time and cannot be guessed.
+func NewID() string {
+	var data [16]byte
+	binary.BigEndian.PutUint64(data[:8], uint64(time.Now().UnixMilli())<<16)
+	if _, err := rand.Read(data[6:]); err != nil {
+		panic(err) // crypto/rand never fails on supported platforms
+	}
+
+	// 128 bits encoded 5 bits at a time, the first character carries 3 bits
+	var out [26]byte
+	hi := binary.BigEndian.Uint64(data[:8])
+	lo := binary.BigEndian.Uint64(data[8:])
+	for i := 25; i >= 0; i-- {
+		out[i] = crockford[lo&0x1f]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+	return string(out[:])
+}
@@ -0,0 +1,31 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNewID(t *testing.T) {
+	seen := make(map[string]bool)
+	previous := ""
+	for i := 0; i < 10000; i++ {
+		id := NewID()
+		if len(id) != 26 {
+			t.Fatalf("expected 26 characters, got %q", id)
+		}
+		for _, r := range id {
+			if !strings.ContainsRune(crockford, r) {
+				t.Fatalf("unexpected character %q in %q", r, id)
+			}
+		}
+		if seen[id] {
+			t.Fatalf("duplicate id %q", id)
+		}
+		seen[id] = true
+		// The timestamp prefix keeps IDs roughly ordered
+		if previous != "" && id[:10] < previous[:10] {
+			t.Fatalf("id %q sorts before %q", id, previous)
+		}
+		previous = id
+	}
+}
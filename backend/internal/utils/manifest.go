@@ -0,0 +1,108 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"math"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/lehaisonagentai3/free-contest/backend/internal/config"
+	"github.com/lehaisonagentai3/free-contest/backend/internal/model"
+)
+
+// Names of the optional manifests of subject and chapter folders
+const (
+	SubjectManifestFile = "subject.json"
+	ChapterManifestFile = "chapter.json"
+)
+
+// SubjectManifest is the optional subject.json of a subject folder. Fields
+// left out are taken from the folder name, so a manifest may only set the
+// description or the order.
+type SubjectManifest struct {
+	Name         string                     `json:"name,omitempty"`
+	Description  string                     `json:"description,omitempty"`
+	TestTime     int                        `json:"test_time,omitempty"` // minutes
+	Order        int                        `json:"order,omitempty"`     // position among the subjects, unordered ones come last
+	RetakePolicy *model.SubjectRetakePolicy `json:"retake_policy,omitempty"`
+}
+
+// ChapterManifest is the optional chapter.json of a chapter folder, see
+// SubjectManifest
+type ChapterManifest struct {
+	Name            string `json:"name,omitempty"`
+	Description     string `json:"description,omitempty"`
+	NumQuestionTest int    `json:"num_question_test,omitempty"` // questions drawn per test
+	Order           int    `json:"order,omitempty"`             // position among the chapters of the subject
+}
+
+// readManifest decodes the manifest at path into v, reporting whether there
+// is one. Unknown fields are errors so that typos do not go unnoticed.
+func readManifest(path string, v any) (bool, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil {
+		return true, err
+	}
+	return true, nil
+}
+
+func (m *SubjectManifest) validate() error {
+	if m.TestTime < 0 {
+		return fmt.Errorf("test_time %d is not a positive number of minutes", m.TestTime)
+	}
+	if policy := m.RetakePolicy; policy != nil {
+		if policy.MaxAttempts < 0 || policy.Cooldown < 0 {
+			return errors.New("max_attempts and cooldown of retake_policy cannot be negative")
+		}
+		switch policy.Scoring {
+		case "", config.ScoringBest, config.ScoringLast, config.ScoringAverage:
+		default:
+			return fmt.Errorf("unknown scoring %q, expected best, last or average", policy.Scoring)
+		}
+	}
+	return nil
+}
+
+func (m *ChapterManifest) validate() error {
+	if m.NumQuestionTest < 0 {
+		return fmt.Errorf("num_question_test %d is not a positive number", m.NumQuestionTest)
+	}
+	return nil
+}
+
+// parseFolderName splits a "Name - number - unit" folder name from the
+// right, so the name may itself contain hyphens
+func parseFolderName(folder string) (name, number string, ok bool) {
+	parts := strings.Split(folder, "-")
+	if len(parts) < 3 {
+		return strings.TrimSpace(folder), "", false
+	}
+	return strings.TrimSpace(strings.Join(parts[:len(parts)-2], "-")), strings.TrimSpace(parts[len(parts)-2]), true
+}
+
+// sortByOrder stably sorts the items by their manifest order, items without
+// one keeping their folder order after the others
+func sortByOrder[T any](items []T, order func(T) int) {
+	key := func(item T) int {
+		if o := order(item); o != 0 {
+			return o
+		}
+		return math.MaxInt
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		return key(items[i]) < key(items[j])
+	})
+}
@@ -0,0 +1,31 @@
+package utils
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"strings"
+)
+
+// MaxQuestionID is the largest ID a question bank may give a question. IDs
+// of questions without one are derived from the question and placed above
+// it, so the two never collide.
+const MaxQuestionID = 1<<31 - 1
+
+// derivedQuestionIDs is how many IDs lie between MaxQuestionID and the
+// largest integer a browser reads exactly from JSON
+const derivedQuestionIDs = 1<<53 - 1 - MaxQuestionID
+
+// QuestionKey returns the text a question is recognised by: its content in
+// lower case with whitespace collapsed
+func QuestionKey(content string) string {
+	return strings.Join(strings.Fields(strings.ToLower(content)), " ")
+}
+
+// deriveQuestionID returns the ID of a question without one in the bank,
+// from its subject, chapter and text. It only changes when one of them does;
+// occurrence tells identical questions of a chapter apart.
+func deriveQuestionID(subject, chapter, content string, occurrence int) int {
+	sum := sha256.Sum256(fmt.Appendf(nil, "%s\x00%s\x00%s\x00%d", QuestionKey(subject), QuestionKey(chapter), QuestionKey(content), occurrence))
+	return MaxQuestionID + 1 + int(binary.BigEndian.Uint64(sum[:8])%derivedQuestionIDs)
+}
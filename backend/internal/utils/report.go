@@ -0,0 +1,111 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Severities of a Problem. Errors stop the contest from loading, warnings are
+// reported only.
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
+// Problem is something wrong in the question bank and where it was found
+type Problem struct {
+	Severity string `json:"severity"`
+	Code     string `json:"code"` // stable identifier, for instance missing_correct
+	Message  string `json:"message"`
+	Path     string `json:"path"` // folder or file, relative to the contest folder, or the officer roster
+	Sheet    string `json:"sheet,omitempty"`
+	Row      int    `json:"row,omitempty"` // 1-based, as shown by Excel
+}
+
+// Location returns where the problem is, for instance
+// `Môn/Chương 1 - 10 - câu/cau_hoi.xlsx, sheet "Sheet1", row 8`
+func (p *Problem) Location() string {
+	location := p.Path
+	if p.Sheet != "" {
+		location += fmt.Sprintf(", sheet %q", p.Sheet)
+	}
+	if p.Row > 0 {
+		location += fmt.Sprintf(", row %d", p.Row)
+	}
+	return location
+}
+
+// ValidationReport lists every problem found while loading a contest folder
+// and, if checked, an officer roster
+type ValidationReport struct {
+	ContestPath string     `json:"contest_path"`
+	Subjects    int        `json:"subjects"`
+	Chapters    int        `json:"chapters"`
+	Questions   int        `json:"questions"`
+	OfficerPath string     `json:"officer_path,omitempty"`
+	Officers    int        `json:"officers,omitempty"`
+	Errors      int        `json:"errors"`
+	Warnings    int        `json:"warnings"`
+	Problems    []*Problem `json:"problems"`
+}
+
+func (r *ValidationReport) add(severity, code, path, sheet string, row int, format string, args ...any) {
+	r.Problems = append(r.Problems, &Problem{
+		Severity: severity,
+		Code:     code,
+		Message:  fmt.Sprintf(format, args...),
+		Path:     path,
+		Sheet:    sheet,
+		Row:      row,
+	})
+	if severity == SeverityError {
+		r.Errors++
+	} else {
+		r.Warnings++
+	}
+}
+
+// HasErrors reports whether the contest cannot be used as it is
+func (r *ValidationReport) HasErrors() bool {
+	return r.Errors > 0
+}
+
+// Error makes a report with errors usable as the error of LoadContestInfo
+func (r *ValidationReport) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "question bank %s has %d errors", r.ContestPath, r.Errors)
+	for _, problem := range r.Problems {
+		if problem.Severity == SeverityError {
+			fmt.Fprintf(&b, "\n  %s: %s", problem.Location(), problem.Message)
+		}
+	}
+	return b.String()
+}
+
+// WriteText writes the report for people, one problem per line
+func (r *ValidationReport) WriteText(w io.Writer) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Question bank %s: %d subjects, %d chapters, %d questions\n", r.ContestPath, r.Subjects, r.Chapters, r.Questions)
+	if r.OfficerPath != "" {
+		fmt.Fprintf(&b, "Officer roster %s: %d officers\n", r.OfficerPath, r.Officers)
+	}
+	if len(r.Problems) == 0 {
+		b.WriteString("No problems found\n")
+	} else {
+		fmt.Fprintf(&b, "%d errors, %d warnings\n\n", r.Errors, r.Warnings)
+		for _, problem := range r.Problems {
+			fmt.Fprintf(&b, "%-7s %s: %s (%s)\n", strings.ToUpper(problem.Severity), problem.Location(), problem.Message, problem.Code)
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// WriteJSON writes the report as indented JSON
+func (r *ValidationReport) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(r)
+}
@@ -25,214 +25,401 @@ func ImportQuestionFromJson(filePath string) ([]model.Question, error) {
 	return questions, nil
 }
 
-func LoadQuestionFromExcel(filePath string) ([]*model.Question, error) {
-	// 1. Mở file Excel
-	f, err := excelize.OpenFile(filePath)
-	if err != nil {
-		return nil, err
+// LoadContestInfo loads a contest folder: one folder per subject named
+// "Tên đề thi - Thời gian - phút", each holding one folder per chapter named
+// "Chương X - số câu hỏi - câu" with the question files. A subject.json or
+// chapter.json in the folder overrides what its name says. Questions keep the
+// ID given in the bank, or one derived from their subject, chapter and text,
+// so adding or removing questions never renumbers the others. When the bank
+// has errors it fails with the *ValidationReport listing all of them.
+func LoadContestInfo(path string) (*model.Contest, error) {
+	contest, report := ValidateContest(path)
+	if report.HasErrors() {
+		return nil, report
 	}
-	defer f.Close()
+	return contest, nil
+}
 
-	if f.SheetCount == 0 {
-		return nil, errors.New("no sheets found in the Excel file")
+// ValidateContest loads a contest folder like LoadContestInfo but carries on
+// past problems, returning what could be loaded together with every problem
+// found and where.
+func ValidateContest(path string) (*model.Contest, *ValidationReport) {
+	report := &ValidationReport{ContestPath: path, Problems: []*Problem{}}
+	folderName := filepath.Base(path)
+	contest := &model.Contest{
+		ID:         1,
+		Name:       folderName,
+		Subjects:   []*model.Subject{},
+		FolderPath: path,
 	}
-
-	sheetName := f.GetSheetList()[0]
-	rows, err := f.GetRows(sheetName)
+	entries, err := os.ReadDir(path)
 	if err != nil {
-		return nil, err
-	}
-
-	if len(rows)%7 != 0 && (len(rows)+1)%7 != 0 {
-		return nil, errors.New("invalid row count, expected multiple of 7")
+		report.add(SeverityError, "unreadable_folder", ".", "", 0, "%v", err)
+		return contest, report
 	}
-
-	var questions []*model.Question
-
-	// 3. Duyệt qua từng row, gom mỗi nhóm 7 dòng thành 1 Question
-	for i := 0; i < len(rows); i++ {
-		row := rows[i]
-		if len(row) < 2 {
-			return nil, errors.New("row must have 2 columns, got " + fmt.Sprint(len(row)))
+	usedIDs := make(map[int]*Problem) // where each question ID was first given
+	subjectID := 0
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
 		}
-		// Dòng bắt đầu bằng "Câu X"
-		if strings.HasPrefix(row[0], "Câu") {
-
-			q := model.Question{
-				ID:      i + 1, // Sử dụng chỉ số dòng làm ID tạm thời
-				Content: row[1],
+		// Files next to the subject folders do not shift their IDs
+		subjectID++
+		subjectPath := filepath.Join(path, entry.Name())
+		subject := loadSubject(report, entry.Name(), subjectPath)
+		if subject == nil {
+			continue
+		}
+		subject.FolderPath = subjectPath
+		subject.ID = subjectID // ID bắt đầu từ 1
+		subject.ContestID = contest.ID
+		// Đọc các chương trong thư mục
+		chapterEntries, err := os.ReadDir(subject.FolderPath)
+		if err != nil {
+			report.add(SeverityError, "unreadable_folder", entry.Name(), "", 0, "%v", err)
+			continue
+		}
+		seen := make(map[string]*Problem) // where each question text of the subject was first found
+		chapterID := 0
+		for _, chapterEntry := range chapterEntries {
+			if !chapterEntry.IsDir() {
+				continue
 			}
-
-			// đảm bảo còn đủ các dòng A, B, C, D, Đáp án
-			if i+5 < len(rows) {
-				optA := rows[i+1]
-				optB := rows[i+2]
-				optC := rows[i+3]
-				optD := rows[i+4]
-				ans := rows[i+5]
-
-				if len(optA) >= 2 {
-					q.AnswerA = optA[1]
-				}
-				if len(optB) >= 2 {
-					q.AnswerB = optB[1]
+			chapterID++
+			chapterPath := chapterEntry.Name()
+			chapterLocation := filepath.Join(entry.Name(), chapterPath)
+			chapter := loadChapter(report, chapterLocation, filepath.Join(subject.FolderPath, chapterPath))
+			if chapter == nil {
+				continue
+			}
+			chapter.ID = chapterID // ID bắt đầu từ 1
+			chapter.SubjectID = subject.ID
+			chapter.FolderPath = filepath.Join(subject.FolderPath, chapterPath)
+			subject.NumQuestionTest += chapter.NumQuestionTest
+			// Đọc các câu hỏi trong chương (câu hỏi là cá file *.xlsx trong thư mục chương)
+			questionFiles, err := os.ReadDir(chapter.FolderPath)
+			if err != nil {
+				report.add(SeverityError, "unreadable_folder", chapterLocation, "", 0, "%v", err)
+				continue
+			}
+			occurrences := make(map[string]int) // how many times each question text was met in the chapter
+			for _, questionFile := range questionFiles {
+				// Excel keeps "~$name.xlsx" lock files next to open workbooks
+				if filepath.Ext(questionFile.Name()) != ".xlsx" || strings.HasPrefix(questionFile.Name(), "~$") {
+					continue
 				}
-				if len(optC) >= 2 {
-					q.AnswerC = optC[1]
+				fileLocation := filepath.Join(chapterLocation, questionFile.Name())
+				sheets, err := ParseQuestionWorkbook(filepath.Join(chapter.FolderPath, questionFile.Name()))
+				if err != nil {
+					report.add(SeverityError, "unreadable_file", fileLocation, "", 0, "cannot read the workbook: %v", err)
+					continue
 				}
-				if len(optD) >= 2 {
-					q.AnswerD = optD[1]
+				if len(sheets) == 0 {
+					report.add(SeverityWarning, "no_questions", fileLocation, "", 0, "no questions found in any sheet")
 				}
-				if len(ans) >= 2 {
-					q.Correct = ans[1]
+				for _, sheet := range sheets {
+					for _, sheetQuestion := range sheet.Questions {
+						checkQuestion(report, seen, fileLocation, sheet.Name, sheetQuestion)
+						assignQuestionID(report, usedIDs, occurrences, subject, chapter, fileLocation, sheet.Name, sheetQuestion)
+						chapter.Questions = append(chapter.Questions, sheetQuestion.Question)
+						report.Questions++
+					}
 				}
-			} else {
-				return nil, errors.New("not enough rows for question options and answer, row " + fmt.Sprint(i+1) + " not enough")
 			}
-
-			questions = append(questions, &q)
-			// bỏ qua nhóm 7 dòng (5 dòng data + 1 dòng blank)
-			i += 6
+			chapter.TotalQuestions = len(chapter.Questions)
+			if chapter.TotalQuestions < chapter.NumQuestionTest {
+				report.add(SeverityError, "not_enough_questions", chapterLocation, "", 0, "%d questions drawn per test but only %d in the chapter", chapter.NumQuestionTest, chapter.TotalQuestions)
+			}
+			subject.Chapters = append(subject.Chapters, chapter)
+			report.Chapters++
 		}
+		sortByOrder(subject.Chapters, func(chapter *model.Chapter) int { return chapter.Order })
+
+		contest.Subjects = append(contest.Subjects, subject)
+		report.Subjects++
 	}
+	sortByOrder(contest.Subjects, func(subject *model.Subject) int { return subject.Order })
+	return contest, report
+}
 
-	// 4. In ra kiểm tra
-	// for _, q := range questions {
-	// 	fmt.Printf("Q%d: %s\n", q.ID, q.Content)
-	// 	fmt.Printf("  A. %s\n", q.AnswerA)
-	// 	fmt.Printf("  B. %s\n", q.AnswerB)
-	// 	fmt.Printf("  C. %s\n", q.AnswerC)
-	// 	fmt.Printf("  D. %s\n", q.AnswerD)
-	// 	fmt.Printf("  -> Đáp án: %s\n\n", q.Correct)
-	// }
-	// This function should implement the logic to read questions from an Excel file
-	// For now, we return an empty slice and nil error
-	return questions, nil
+// loadSubject reads the name, description, test time, order and retake
+// policy of a subject from its subject.json, falling back to the folder name
+// for what the manifest leaves out. It returns nil if the subject cannot be
+// used.
+func loadSubject(report *ValidationReport, folder, folderPath string) *model.Subject {
+	manifestLocation := filepath.Join(folder, SubjectManifestFile)
+	var manifest SubjectManifest
+	hasManifest, err := readManifest(filepath.Join(folderPath, SubjectManifestFile), &manifest)
+	if err == nil {
+		err = manifest.validate()
+	}
+	if err != nil {
+		report.add(SeverityError, "invalid_manifest", manifestLocation, "", 0, "%v", err)
+		return nil
+	}
+	// Giả sử tên thư mục là "Tên đề thi - Thời gian - phút"
+	name, testTimeText, parsed := parseFolderName(folder)
+	if !parsed && !hasManifest {
+		report.add(SeverityError, "invalid_subject_folder", folder, "", 0, "subject folder name must look like \"Tên đề thi - Thời gian - phút\" or the folder must have a %s", SubjectManifestFile)
+		return nil
+	}
+
+	subject := &model.Subject{
+		Name:         name,
+		TestTime:     manifest.TestTime,
+		Order:        manifest.Order,
+		RetakePolicy: manifest.RetakePolicy,
+	}
+	if manifest.Name != "" {
+		subject.Name = strings.TrimSpace(manifest.Name)
+	}
+	subject.Description = subject.Name
+	if manifest.Description != "" {
+		subject.Description = manifest.Description
+	}
+	if subject.TestTime == 0 {
+		testTime, err := strconv.Atoi(testTimeText)
+		switch {
+		case !parsed:
+			report.add(SeverityError, "invalid_test_time", manifestLocation, "", 0, "test_time is not set and the folder name does not give one")
+		case err != nil || testTime <= 0:
+			report.add(SeverityError, "invalid_test_time", folder, "", 0, "test time %q is not a positive number of minutes", testTimeText)
+		}
+		subject.TestTime = testTime
+	}
+	return subject
 }
 
-// /Users/maianhnguyen/go/src/github.com/lehaisonagentai3/free-contest/backend/Kỳ thi sĩ quan phân đội
-func LoadContestInfo(path string) (*model.Contest, error) {
-	folderName := filepath.Base(path)
-	contest := &model.Contest{
-		ID:         1,
-		Name:       folderName,
-		Subjects:   []*model.Subject{},
-		FolderPath: path,
+// loadChapter reads the name, description, question count and order of a
+// chapter from its chapter.json, falling back to the folder name, see
+// loadSubject
+func loadChapter(report *ValidationReport, location, folderPath string) *model.Chapter {
+	manifestLocation := filepath.Join(location, ChapterManifestFile)
+	var manifest ChapterManifest
+	hasManifest, err := readManifest(filepath.Join(folderPath, ChapterManifestFile), &manifest)
+	if err == nil {
+		err = manifest.validate()
 	}
-	entries, err := os.ReadDir(path)
 	if err != nil {
-		return nil, err
+		report.add(SeverityError, "invalid_manifest", manifestLocation, "", 0, "%v", err)
+		return nil
+	}
+	// Giả sử tên chương là "chương X - số câu hỏi - câu"
+	name, countText, parsed := parseFolderName(filepath.Base(location))
+	if !parsed && !hasManifest {
+		report.add(SeverityError, "invalid_chapter_folder", location, "", 0, "chapter folder name must look like \"Chương X - số câu hỏi - câu\" or the folder must have a %s", ChapterManifestFile)
+		return nil
 	}
-	questionIDCounter := 0
-	for id, entry := range entries {
-		if entry.IsDir() {
-			subjectPath := filepath.Join(path, entry.Name())
-			subjectParts := strings.Split(entry.Name(), "-")
-			if len(subjectParts) < 3 {
-				return nil, fmt.Errorf("invalid subject folder name: %s", entry.Name())
-			}
-			// Giả sử tên thư mục là "Tên đề thi - Thời gian - phút"
-			subjectName := strings.TrimSpace(subjectParts[0])
-			testTime, _ := strconv.ParseInt(strings.TrimSpace(subjectParts[1]), 10, 64)
-			subject := &model.Subject{
-				Name:        subjectName,
-				Description: subjectName,
-				FolderPath:  subjectPath,
-				TestTime:    int(testTime),
-				ID:          id + 1, // ID bắt đầu từ 1
-				ContestID:   contest.ID,
-			}
-			// Đọc các chương trong thư mục
-			chapterEntries, err := os.ReadDir(subject.FolderPath)
-			if err != nil {
-				return nil, err
-			}
-			for chapterID, chapterEntry := range chapterEntries {
-				if chapterEntry.IsDir() {
-					chapterPath := chapterEntry.Name()
-					// Giả sử tên chương là "chương X - số câu hỏi - câu"
-					chapterParts := strings.Split(chapterPath, "-")
-					if len(chapterParts) < 3 {
-						return nil, fmt.Errorf("invalid chapter folder name: %s", chapterPath)
-					}
-					chapterName := strings.TrimSpace(chapterParts[0])
-					numberTestQuestion, _ := strconv.Atoi(strings.TrimSpace(chapterParts[1]))
-					if numberTestQuestion <= 0 {
-						return nil, fmt.Errorf("invalid number of questions in chapter: %s", chapterName)
-					}
-					chapter := &model.Chapter{
-						ID:              chapterID + 1, // ID bắt đầu từ 1
-						SubjectID:       subject.ID,
-						Name:            chapterName,
-						NumQuestionTest: numberTestQuestion, // Giả sử mỗi chương có 20 câu
-						FolderPath:      filepath.Join(subject.FolderPath, chapterPath),
-					}
-					subject.NumQuestionTest += chapter.NumQuestionTest
-					// Đọc các câu hỏi trong chương (câu hỏi là cá file *.xlsx trong thư mục chương)
-					questionFiles, err := os.ReadDir(chapter.FolderPath)
-					if err != nil {
-						return nil, err
-					}
-					for _, questionFile := range questionFiles {
-						if filepath.Ext(questionFile.Name()) == ".xlsx" {
-							listQuestion, err := LoadQuestionFromExcel(filepath.Join(chapter.FolderPath, questionFile.Name()))
-							if err != nil {
-								return nil, err
-							}
-							if len(listQuestion) < chapter.NumQuestionTest {
-								return nil, fmt.Errorf("not enough questions in chapter %s, expected %d, got %d", chapter.Name, chapter.NumQuestionTest, len(listQuestion))
-							}
-							for _, question := range listQuestion {
-								question.ID = questionIDCounter
-								questionIDCounter++
-								chapter.Questions = append(chapter.Questions, question)
-							}
-							chapter.TotalQuestions += len(listQuestion)
-						}
-					}
-					subject.Chapters = append(subject.Chapters, chapter)
 
-				}
-			}
+	chapter := &model.Chapter{
+		Name:            name,
+		Description:     manifest.Description,
+		NumQuestionTest: manifest.NumQuestionTest,
+		Order:           manifest.Order,
+	}
+	if manifest.Name != "" {
+		chapter.Name = strings.TrimSpace(manifest.Name)
+	}
+	if chapter.NumQuestionTest == 0 {
+		count, err := strconv.Atoi(countText)
+		switch {
+		case !parsed:
+			report.add(SeverityError, "invalid_question_count", manifestLocation, "", 0, "num_question_test is not set and the folder name does not give one")
+			return nil
+		case err != nil || count <= 0:
+			report.add(SeverityError, "invalid_question_count", location, "", 0, "number of questions per test %q is not a positive number", countText)
+			return nil
+		}
+		chapter.NumQuestionTest = count
+	}
+	return chapter
+}
 
-			contest.Subjects = append(contest.Subjects, subject)
+// assignQuestionID gives a question the ID written in the bank, or one
+// derived from its subject, chapter and text, and reports IDs that are
+// invalid or already used. occurrences counts the question texts met so far
+// in the chapter.
+func assignQuestionID(report *ValidationReport, usedIDs map[int]*Problem, occurrences map[string]int, subject *model.Subject, chapter *model.Chapter, path, sheet string, sheetQuestion *SheetQuestion) {
+	question, row := sheetQuestion.Question, sheetQuestion.Row
+	if sheetQuestion.ID != "" {
+		id, err := strconv.Atoi(sheetQuestion.ID)
+		if err != nil || id <= 0 || id > MaxQuestionID {
+			report.add(SeverityError, "invalid_question_id", path, sheet, row, "question ID %q is not a number from 1 to %d", sheetQuestion.ID, MaxQuestionID)
+		} else {
+			question.ID = id
 		}
 	}
-	return contest, nil
+	if question.ID == 0 {
+		key := QuestionKey(question.Content)
+		question.ID = deriveQuestionID(subject.Name, chapter.Name, key, occurrences[key])
+		occurrences[key]++
+	}
+	if first, ok := usedIDs[question.ID]; ok {
+		report.add(SeverityError, "duplicate_question_id", path, sheet, row, "question ID %d is already used at %s", question.ID, first.Location())
+		return
+	}
+	usedIDs[question.ID] = &Problem{Path: path, Sheet: sheet, Row: row}
+}
+
+// checkQuestion reports the problems of one question. seen maps the
+// normalized text of the questions met so far in the subject to where they
+// are, to report duplicates.
+func checkQuestion(report *ValidationReport, seen map[string]*Problem, path, sheet string, sheetQuestion *SheetQuestion) {
+	question, row := sheetQuestion.Question, sheetQuestion.Row
+	if strings.TrimSpace(question.Content) == "" {
+		report.add(SeverityError, "empty_content", path, sheet, row, "question has no text")
+	} else {
+		key := QuestionKey(question.Content)
+		if first, ok := seen[key]; ok {
+			report.add(SeverityWarning, "duplicate_question", path, sheet, row, "same question as %s", first.Location())
+		} else {
+			seen[key] = &Problem{Path: path, Sheet: sheet, Row: row}
+		}
+	}
+	options := map[string]string{"A": question.AnswerA, "B": question.AnswerB, "C": question.AnswerC, "D": question.AnswerD}
+	for _, letter := range model.OptionLetters {
+		if strings.TrimSpace(options[letter]) == "" {
+			report.add(SeverityWarning, "empty_option", path, sheet, row, "option %s is empty", letter)
+		}
+	}
+	switch text, ok := options[question.Correct]; {
+	case question.Correct == "":
+		report.add(SeverityError, "missing_correct", path, sheet, row, "correct answer is missing")
+	case !ok:
+		report.add(SeverityError, "invalid_correct", path, sheet, row, "correct answer %q is not one of A, B, C or D", question.Correct)
+	case strings.TrimSpace(text) == "":
+		report.add(SeverityError, "correct_option_empty", path, sheet, row, "correct answer %s is an empty option", question.Correct)
+	}
 }
 
+// LoadOfficers reads the officer roster: ID, name, rank, position, unit and
+// an optional PIN per row of the first sheet. Rows with fewer than five
+// columns are skipped.
 func LoadOfficers(path string) ([]*model.Officer, error) {
-	f, err := excelize.OpenFile(path)
+	_, rows, err := readOfficerRows(path)
 	if err != nil {
 		return nil, err
 	}
+
+	var officers []*model.Officer
+	for _, row := range rows {
+		if len(row) < 5 {
+			continue
+		}
+		officers = append(officers, parseOfficer(row))
+	}
+	return officers, nil
+}
+
+// readOfficerRows returns the name and rows of the first sheet of the roster
+func readOfficerRows(path string) (string, [][]string, error) {
+	f, err := excelize.OpenFile(path)
+	if err != nil {
+		return "", nil, err
+	}
 	defer f.Close()
 
 	if f.SheetCount == 0 {
-		return nil, errors.New("no sheets found in the Excel file")
+		return "", nil, errors.New("no sheets found in the Excel file")
 	}
 
 	sheetName := f.GetSheetList()[0]
-
-	var officers []*model.Officer
 	rows, err := f.GetRows(sheetName)
 	if err != nil {
-		return nil, err
+		return "", nil, err
 	}
+	return sheetName, rows, nil
+}
 
-	for _, row := range rows {
+func parseOfficer(row []string) *model.Officer {
+	id, _ := strconv.Atoi(row[0])
+	officer := &model.Officer{
+		ID:       id,
+		Name:     row[1],
+		Rank:     row[2],
+		Position: row[3],
+		Unit:     row[4],
+	}
+	// Cột thứ 6 (tùy chọn) là mã PIN dùng để đăng nhập
+	if len(row) > 5 {
+		officer.PIN = strings.TrimSpace(row[5])
+	}
+	return officer
+}
+
+// ValidateOfficers reads an officer roster like LoadOfficers and adds its
+// problems to the report, returning the officers that can be used. A first
+// row whose ID is not a number is taken as the header.
+func (r *ValidationReport) ValidateOfficers(path string) []*model.Officer {
+	r.OfficerPath = path
+	sheetName, rows, err := readOfficerRows(path)
+	if err != nil {
+		r.add(SeverityError, "unreadable_file", path, "", 0, "cannot read the roster: %v", err)
+		return nil
+	}
+
+	var officers []*model.Officer
+	seen := make(map[int]int) // officer ID to row
+	for i, row := range rows {
+		line := i + 1
+		if isBlankRow(row) {
+			continue
+		}
 		if len(row) < 5 {
+			r.add(SeverityWarning, "skipped_row", path, sheetName, line, "row has %d columns instead of ID, name, rank, position and unit, it is skipped", len(row))
 			continue
 		}
-		id, _ := strconv.Atoi(row[0])
-		officer := &model.Officer{
-			ID:       id,
-			Name:     row[1],
-			Rank:     row[2],
-			Position: row[3],
-			Unit:     row[4],
+		officer := parseOfficer(row)
+		if officer.ID <= 0 {
+			if line > 1 {
+				r.add(SeverityError, "invalid_officer_id", path, sheetName, line, "officer ID %q is not a positive number", strings.TrimSpace(row[0]))
+			}
+			continue
+		}
+		if previous, ok := seen[officer.ID]; ok {
+			r.add(SeverityError, "duplicate_officer_id", path, sheetName, line, "officer ID %d is already used on row %d", officer.ID, previous)
+			continue
+		}
+		seen[officer.ID] = line
+		if strings.TrimSpace(officer.Name) == "" {
+			r.add(SeverityWarning, "missing_name", path, sheetName, line, "officer %d has no name", officer.ID)
+		}
+		if officer.PIN == "" {
+			r.add(SeverityWarning, "missing_pin", path, sheetName, line, "officer %d has no PIN and cannot log in", officer.ID)
 		}
 		officers = append(officers, officer)
 	}
-	return officers, nil
+	r.Officers = len(officers)
+	return officers
+}
+
+// LoadAccommodations reads the time accommodations of officers from a JSON
+// array. A missing file means no accommodations.
+func LoadAccommodations(path string) ([]*model.Accommodation, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var accommodations []*model.Accommodation
+	if err := json.Unmarshal(data, &accommodations); err != nil {
+		return nil, fmt.Errorf("decode accommodations %s: %w", path, err)
+	}
+	return accommodations, nil
+}
+
+// SaveAccommodations writes the accommodations as an indented JSON array,
+// replacing the file only once it is fully written
+func SaveAccommodations(path string, accommodations []*model.Accommodation) error {
+	data, err := json.MarshalIndent(accommodations, "", "    ")
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
 }
@@ -0,0 +1,216 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// writeWorkbook writes the rows to the first sheet of a new workbook
+func writeWorkbook(t *testing.T, path string, rows [][]any) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	f := excelize.NewFile()
+	defer f.Close()
+	for i, row := range rows {
+		cell, _ := excelize.CoordinatesToCellName(1, i+1)
+		f.SetSheetRow("Sheet1", cell, &row)
+	}
+	if err := f.SaveAs(path); err != nil {
+		t.Fatalf("Failed to write workbook: %v", err)
+	}
+}
+
+func TestValidateContestReportsEveryProblem(t *testing.T) {
+	root := t.TempDir()
+	header := []any{"Nội dung", "A", "B", "C", "D", "Đáp án"}
+	writeWorkbook(t, filepath.Join(root, "Điều lệnh - 30 - phút", "Chương 1 - 2 - câu", "cau_hoi.xlsx"), [][]any{
+		header,
+		{"Câu đúng", "1", "2", "3", "4", "A"},
+		{"Thiếu đáp án", "1", "2", "3", "4", ""},
+		{"Đáp án sai", "1", "2", "3", "4", "E"},
+		{"Thiếu phương án", "1", "2", "3", "", "D"},
+		{"câu  ĐÚNG", "1", "2", "3", "4", "B"},
+	})
+	writeWorkbook(t, filepath.Join(root, "Điều lệnh - 30 - phút", "Chương 2 - 5 - câu", "cau_hoi.xlsx"), [][]any{
+		header,
+		{"Một câu", "1", "2", "3", "4", "C"},
+	})
+	if err := os.MkdirAll(filepath.Join(root, "Môn không đúng tên"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	_, report := ValidateContest(root)
+	codes := make(map[string]*Problem)
+	for _, problem := range report.Problems {
+		codes[problem.Code] = problem
+	}
+	for _, code := range []string{"invalid_subject_folder", "missing_correct", "invalid_correct", "correct_option_empty", "empty_option", "duplicate_question", "not_enough_questions"} {
+		if codes[code] == nil {
+			t.Errorf("expected a %s problem, got %+v", code, report.Problems)
+		}
+	}
+	if problem := codes["missing_correct"]; problem != nil && (problem.Sheet != "Sheet1" || problem.Row != 3 || !strings.HasSuffix(problem.Path, "cau_hoi.xlsx")) {
+		t.Errorf("expected the location of the question, got %+v", problem)
+	}
+	if problem := codes["duplicate_question"]; problem != nil && (problem.Severity != SeverityWarning || !strings.Contains(problem.Message, "row 2")) {
+		t.Errorf("expected the duplicate to point at the first one, got %+v", problem)
+	}
+	if report.Subjects != 1 || report.Chapters != 2 || report.Questions != 6 || report.Errors != 5 || report.Warnings != 2 {
+		t.Errorf("unexpected counts %+v", report)
+	}
+
+	var text, data bytes.Buffer
+	if err := report.WriteText(&text); err != nil || !strings.Contains(text.String(), "ERROR") {
+		t.Errorf("unexpected text report %q: %v", text.String(), err)
+	}
+	var decoded ValidationReport
+	if err := report.WriteJSON(&data); err != nil || json.Unmarshal(data.Bytes(), &decoded) != nil || len(decoded.Problems) != len(report.Problems) {
+		t.Errorf("unexpected JSON report %s: %v", data.String(), err)
+	}
+
+	_, err := LoadContestInfo(root)
+	var failed *ValidationReport
+	if !errors.As(err, &failed) || failed.Errors != report.Errors {
+		t.Errorf("expected LoadContestInfo to fail with the report, got %v", err)
+	}
+}
+
+func TestValidateOfficersMatchesLoadOfficers(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "officers.xlsx")
+	writeWorkbook(t, path, [][]any{
+		{"ID", "Họ tên", "Cấp bậc", "Chức vụ", "Đơn vị", "PIN"},
+		{1, "Nguyễn Văn A", "Đại úy", "Đại đội trưởng", "Đại đội 1", "1111"},
+		{2, "Trần Văn B", "Thượng úy", "Trung đội trưởng", "Đại đội 1"},
+		{1, "Lê Văn C", "Trung úy", "Trung đội trưởng", "Đại đội 2", "3333"},
+		{"x", "Phạm Văn D", "Trung úy", "Trung đội trưởng", "Đại đội 2", "4444"},
+		{3, "Hoàng Văn E"},
+	})
+
+	var report ValidationReport
+	officers := report.ValidateOfficers(path)
+	codes := make(map[string]*Problem)
+	for _, problem := range report.Problems {
+		codes[problem.Code] = problem
+	}
+	for code, row := range map[string]int{"missing_pin": 3, "duplicate_officer_id": 4, "invalid_officer_id": 5, "skipped_row": 6} {
+		if problem := codes[code]; problem == nil || problem.Row != row {
+			t.Errorf("expected a %s problem on row %d, got %+v", code, row, report.Problems)
+		}
+	}
+	if len(officers) != 2 || report.Officers != 2 || report.Errors != 2 || report.Warnings != 2 {
+		t.Errorf("unexpected result %d officers, report %+v", len(officers), report)
+	}
+
+	loaded, err := LoadOfficers(path)
+	if err != nil || len(loaded) != 5 || loaded[1].PIN != "1111" || loaded[2].PIN != "" {
+		t.Errorf("expected LoadOfficers to keep every full row, got %d: %v", len(loaded), err)
+	}
+}
+
+func TestValidateContestReadsManifests(t *testing.T) {
+	root := t.TempDir()
+	header := []any{"Nội dung", "A", "B", "C", "D", "Đáp án"}
+	question := []any{"Câu hỏi", "1", "2", "3", "4", "A"}
+	writeManifest := func(path, data string) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Names with hyphens, read from the folder names only
+	writeWorkbook(t, filepath.Join(root, "Điều lệnh - Đội ngũ - 45 - phút", "Chương 1 - Tổng quát - 1 - câu", "cau_hoi.xlsx"), [][]any{header, question})
+
+	// Manifests for everything but the question count of one chapter
+	shooting := filepath.Join(root, "Bắn súng")
+	writeWorkbook(t, filepath.Join(shooting, "Phần lý thuyết", "cau_hoi.xlsx"), [][]any{header, question, {"Câu khác", "1", "2", "3", "4", "B"}})
+	writeWorkbook(t, filepath.Join(shooting, "Phần thực hành - 1 - câu", "cau_hoi.xlsx"), [][]any{header, {"Câu thực hành", "1", "2", "3", "4", "C"}})
+	writeManifest(filepath.Join(shooting, SubjectManifestFile), `{"name": "Bắn súng K54", "description": "Súng ngắn K54", "test_time": 20, "order": 1, "retake_policy": {"max_attempts": 2, "scoring": "last"}}`)
+	writeManifest(filepath.Join(shooting, "Phần lý thuyết", ChapterManifestFile), `{"num_question_test": 2, "order": 2}`)
+	writeManifest(filepath.Join(shooting, "Phần thực hành - 1 - câu", ChapterManifestFile), `{"name": "Thực hành", "order": 1}`)
+
+	// A typo in a manifest
+	writeWorkbook(t, filepath.Join(root, "Hậu cần - 30 - phút", "Chương 1 - 1 - câu", "cau_hoi.xlsx"), [][]any{header, question})
+	writeManifest(filepath.Join(root, "Hậu cần - 30 - phút", SubjectManifestFile), `{"test_tme": 30}`)
+
+	contest, report := ValidateContest(root)
+	if len(report.Problems) != 1 || report.Problems[0].Code != "invalid_manifest" || !strings.HasSuffix(report.Problems[0].Path, SubjectManifestFile) {
+		t.Fatalf("expected only the manifest typo to be reported, got %+v", report.Problems)
+	}
+	if len(contest.Subjects) != 2 {
+		t.Fatalf("expected 2 subjects, got %d", len(contest.Subjects))
+	}
+	first, second := contest.Subjects[0], contest.Subjects[1]
+	if first.Name != "Bắn súng K54" || first.Description != "Súng ngắn K54" || first.TestTime != 20 || first.NumQuestionTest != 3 {
+		t.Errorf("expected the ordered subject from its manifest first, got %+v", first)
+	}
+	if policy := first.RetakePolicy; policy == nil || policy.MaxAttempts != 2 || policy.Scoring != "last" {
+		t.Errorf("unexpected retake policy %+v", policy)
+	}
+	if chapters := first.Chapters; chapters[0].Name != "Thực hành" || chapters[0].NumQuestionTest != 1 || chapters[1].Name != "Phần lý thuyết" || chapters[1].NumQuestionTest != 2 {
+		t.Errorf("unexpected chapters %+v %+v", chapters[0], chapters[1])
+	}
+	if second.Name != "Điều lệnh - Đội ngũ" || second.TestTime != 45 || second.Chapters[0].Name != "Chương 1 - Tổng quát" {
+		t.Errorf("expected hyphens to be kept in names, got %+v", second)
+	}
+}
+
+func TestQuestionIDsSurviveBankEdits(t *testing.T) {
+	root := t.TempDir()
+	file := filepath.Join(root, "Điều lệnh - 30 - phút", "Chương 1 - 1 - câu", "cau_hoi.xlsx")
+	header := []any{"Mã", "Nội dung", "A", "B", "C", "D", "Đáp án"}
+	questions := [][]any{
+		{"", "Câu một", "1", "2", "3", "4", "A"},
+		{"", "Câu hai", "1", "2", "3", "4", "B"},
+		{1024, "Câu ba", "1", "2", "3", "4", "C"},
+	}
+	writeWorkbook(t, file, append([][]any{header}, questions...))
+	ids := func() map[string]int {
+		t.Helper()
+		contest, err := LoadContestInfo(root)
+		if err != nil {
+			t.Fatalf("Failed to load contest: %v", err)
+		}
+		byContent := make(map[string]int)
+		for _, question := range contest.Subjects[0].Chapters[0].Questions {
+			byContent[question.Content] = question.ID
+		}
+		return byContent
+	}
+	before := ids()
+	if before["Câu ba"] != 1024 || before["Câu một"] <= MaxQuestionID || before["Câu hai"] <= MaxQuestionID || before["Câu một"] == before["Câu hai"] {
+		t.Fatalf("expected the given ID and two derived ones, got %v", before)
+	}
+
+	// A question added in front and the options of another fixed
+	questions[1][2] = "Một"
+	writeWorkbook(t, file, append([][]any{header, {"", "Câu mới", "1", "2", "3", "4", "D"}}, questions...))
+	after := ids()
+	for content, id := range before {
+		if after[content] != id {
+			t.Errorf("ID of %q changed from %d to %d", content, id, after[content])
+		}
+	}
+
+	writeWorkbook(t, file, append([][]any{header, {1024, "Câu trùng mã", "1", "2", "3", "4", "D"}, {"abc", "Câu sai mã", "1", "2", "3", "4", "D"}}, questions...))
+	_, report := ValidateContest(root)
+	codes := make(map[string]*Problem)
+	for _, problem := range report.Problems {
+		codes[problem.Code] = problem
+	}
+	if problem := codes["duplicate_question_id"]; problem == nil || problem.Row != 6 || !strings.Contains(problem.Message, "row 2") {
+		t.Errorf("expected the second use of ID 1024 to be reported, got %+v", report.Problems)
+	}
+	if problem := codes["invalid_question_id"]; problem == nil || problem.Row != 3 {
+		t.Errorf("expected the invalid ID to be reported, got %+v", report.Problems)
+	}
+}
@@ -11,7 +11,13 @@ import {
   ListSubjectResponse,
   ListUnitResponse,
   TestResponse,
-  SubmissionResponse
+  SubmissionResponse,
+  LoginData,
+  LoginResponse,
+  SavedAnswer,
+  SavedAnswersResponse,
+  TestClock,
+  ClockResponse
 } from '../types/api';
 
 const API_BASE_URL = process.env.NODE_ENV === 'production' 
@@ -25,6 +31,25 @@ const api = axios.create({
   },
 });
 
+// Attach the session token to every request
+api.interceptors.request.use((config) => {
+  const token = localStorage.getItem('authToken');
+  if (token) {
+    config.headers.Authorization = `Bearer ${token}`;
+  }
+  return config;
+});
+
+// Auth API
+export const login = async (officerId: string | number, pin: string): Promise<LoginData> => {
+  const response: AxiosResponse<LoginResponse> = await api.post('/auth/login', {
+    officer_id: Number(officerId),
+    pin,
+  });
+  localStorage.setItem('authToken', response.data.data.token);
+  return response.data.data;
+};
+
 // Officers API
 export const getOfficers = async (): Promise<Officer[]> => {
   const response: AxiosResponse<ListOfficerResponse> = await api.get('/officers');
@@ -50,12 +75,10 @@ export const getUnits = async (): Promise<Unit[]> => {
 
 // Tests API
 export const getOfficerSubjectTest = async (
-  officerId: string | number,
   subjectId: string | number
 ): Promise<Test> => {
   const response: AxiosResponse<TestResponse> = await api.get('/tests/officer-subject', {
     params: {
-      officerID: officerId,
       subjectID: subjectId,
     },
   });
@@ -63,12 +86,32 @@ export const getOfficerSubjectTest = async (
 };
 
 export const startTest = async (
-  officerId: string | number,
   testId: string | number
 ): Promise<Test> => {
   const response: AxiosResponse<TestResponse> = await api.post('/tests/start', null, {
     params: {
-      officerID: officerId,
+      testID: testId,
+    },
+  });
+  return response.data.data;
+};
+
+export const getTestQuestions = async (
+  testId: string
+): Promise<Test> => {
+  const response: AxiosResponse<TestResponse> = await api.get('/tests/questions', {
+    params: {
+      testID: testId,
+    },
+  });
+  return response.data.data;
+};
+
+export const getHeartbeat = async (
+  testId: string
+): Promise<TestClock> => {
+  const response: AxiosResponse<ClockResponse> = await api.get('/tests/heartbeat', {
+    params: {
       testID: testId,
     },
   });
@@ -76,15 +119,29 @@ export const startTest = async (
 };
 
 export const submitTest = async (
-  officerId: string | number,
   testId: string | number,
-  answers: TestAnswers
+  answers: TestAnswers,
+  idempotencyKey?: string
 ): Promise<Submission> => {
   const response: AxiosResponse<SubmissionResponse> = await api.post('/tests/submit', answers, {
     params: {
-      officerID: officerId,
       testID: testId,
     },
+    headers: idempotencyKey ? { 'Idempotency-Key': idempotencyKey } : undefined,
+  });
+  return response.data.data;
+};
+
+export const saveAnswers = async (
+  testId: string,
+  answers: TestAnswers,
+  idempotencyKey?: string
+): Promise<Record<string, SavedAnswer>> => {
+  const response: AxiosResponse<SavedAnswersResponse> = await api.put('/tests/answers', answers, {
+    params: {
+      testID: testId,
+    },
+    headers: idempotencyKey ? { 'Idempotency-Key': idempotencyKey } : undefined,
   });
   return response.data.data;
 };
@@ -8,6 +8,7 @@ const Navigation: React.FC<NavigationProps> = ({ officerId, setOfficerId }) => {
   const handleLogout = (): void => {
     setOfficerId('');
     localStorage.removeItem('officerId');
+    localStorage.removeItem('authToken');
     localStorage.removeItem('lastSubmission');
     navigate('/login');
   };
@@ -49,8 +49,8 @@ const LeaderBoardPage: React.FC = () => {
           bValue = b.unit || '';
           break;
         case 'tests':
-          aValue = a.list_submission?.length || 0;
-          bValue = b.list_submission?.length || 0;
+          aValue = a.subjects?.length || 0;
+          bValue = b.subjects?.length || 0;
           break;
         default:
           aValue = a.score || 0;
@@ -138,7 +138,7 @@ const LeaderBoardPage: React.FC = () => {
           
           <div style={{ textAlign: 'center' }}>
             <div style={{ fontSize: '24px', fontWeight: 'bold', color: '#ffc107' }}>
-              {officers.reduce((sum, o) => sum + (o.list_submission?.length || 0), 0)}
+              {officers.reduce((sum, o) => sum + (o.subjects?.length || 0), 0)}
             </div>
             <div style={{ color: '#666' }}>Tổng bài thi đã làm</div>
           </div>
@@ -208,12 +208,12 @@ const LeaderBoardPage: React.FC = () => {
                       {officer.score || 0}
                     </td>
                     <td>
-                      {officer.list_submission && officer.list_submission.length > 0 ? (
+                      {officer.subjects && officer.subjects.length > 0 ? (
                         <div>
                           <div style={{ fontWeight: 'bold', marginBottom: '4px' }}>
-                            Đã làm {officer.list_submission.length} bài thi
+                            Đã làm {officer.subjects.length} bài thi
                           </div>
-                          {officer.list_submission.map((submission, idx) => (
+                          {officer.subjects.map((submission, idx) => (
                             <div key={idx} style={{ 
                               fontSize: '12px', 
                               color: '#666', 
@@ -236,7 +236,7 @@ const LeaderBoardPage: React.FC = () => {
                           ))}
                           <div style={{ fontSize: '11px', color: '#999', marginTop: '4px' }}>
                             Lần cuối: {new Date(
-                              Math.max(...officer.list_submission.map(s => s.submitted_at)) * 1000
+                              Math.max(...officer.subjects.map(s => s.submitted_at)) * 1000
                             ).toLocaleDateString('vi-VN')}
                           </div>
                         </div>
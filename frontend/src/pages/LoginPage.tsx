@@ -1,11 +1,12 @@
 import React, { useState } from 'react';
 import { useNavigate } from 'react-router-dom';
-import { getOfficerById } from '../api/api';
+import { login } from '../api/api';
 import { LoginPageProps } from '../types/api';
 import logo from '../logo.jpg';
 
 const LoginPage: React.FC<LoginPageProps> = ({ setOfficerId }) => {
   const [inputOfficerId, setInputOfficerId] = useState<string>('');
+  const [inputPin, setInputPin] = useState<string>('');
   const [loading, setLoading] = useState<boolean>(false);
   const [error, setError] = useState<string>('');
   const navigate = useNavigate();
@@ -16,20 +17,27 @@ const LoginPage: React.FC<LoginPageProps> = ({ setOfficerId }) => {
       setError('Vui lòng nhập mã cán bộ');
       return;
     }
+    if (!inputPin.trim()) {
+      setError('Vui lòng nhập mã PIN');
+      return;
+    }
 
     setLoading(true);
     setError('');
 
     try {
-      // Validate officer exists
-      await getOfficerById(inputOfficerId);
+      // Check credentials and keep the session token
+      await login(inputOfficerId, inputPin.trim());
       
       // Set officer ID and navigate
       setOfficerId(inputOfficerId);
       navigate('/select-subject');
     } catch (err: any) {
-      if (err.response?.status === 404) {
-        setError('Không tìm thấy cán bộ. Vui lòng kiểm tra lại mã cán bộ.');
+      if (err.response?.status === 401) {
+        setError('Mã thí sinh hoặc mã PIN không đúng. Vui lòng kiểm tra lại.');
+      } else if (err.response?.status === 429) {
+        const minutes = Math.ceil(Number(err.response.headers?.['retry-after'] || 60) / 60);
+        setError(`Đăng nhập sai quá nhiều lần. Vui lòng thử lại sau ${minutes} phút.`);
       } else {
         setError('Đăng nhập thất bại. Vui lòng thử lại.');
       }
@@ -72,6 +80,19 @@ const LoginPage: React.FC<LoginPageProps> = ({ setOfficerId }) => {
               required
             />
           </div>
+
+          <div className="input-group">
+            <label htmlFor="pin">Mã PIN</label>
+            <input
+              type="password"
+              id="pin"
+              value={inputPin}
+              onChange={(e) => setInputPin(e.target.value)}
+              placeholder="Nhập mã PIN của bạn"
+              disabled={loading}
+              required
+            />
+          </div>
           
           <button 
             type="submit" 
@@ -84,7 +105,7 @@ const LoginPage: React.FC<LoginPageProps> = ({ setOfficerId }) => {
         </form>
         
         <div style={{ textAlign: 'center', marginTop: '20px', color: '#666' }}>
-          <p>Nhập mã thí sinh để truy cập hệ thống thi</p>
+          <p>Nhập mã thí sinh và mã PIN để truy cập hệ thống thi</p>
         </div>
       </div>
     </div>
@@ -34,8 +34,8 @@ const SelectSubjectPage: React.FC<SelectSubjectPageProps> = ({ officerId }) => {
   }, [officerId]);
 
   const getCompletedSubjects = (): Set<number> => {
-    if (!officer?.list_submission) return new Set();
-    return new Set(officer.list_submission.map(sub => sub.subject_id));
+    if (!officer?.subjects) return new Set();
+    return new Set(officer.subjects.map(subject => subject.subject_id));
   };
 
   const handleSubjectSelect = (subjectId: number): void => {
@@ -90,7 +90,7 @@ const SelectSubjectPage: React.FC<SelectSubjectPageProps> = ({ officerId }) => {
             </div>
             <div className="info-item">
               <div className="info-label">Số bài thi đã hoàn thành</div>
-              <div className="info-value">{officer.list_submission?.length || 0}</div>
+              <div className="info-value">{officer.subjects?.length || 0}</div>
             </div>
           </div>
         )}
@@ -105,7 +105,7 @@ const SelectSubjectPage: React.FC<SelectSubjectPageProps> = ({ officerId }) => {
           <div className="subject-grid">
             {subjects.map((subject) => {
               const isCompleted = completedSubjects.has(subject.id);
-              const submission = officer?.list_submission?.find(sub => sub.subject_id === subject.id);
+              const subjectScore = officer?.subjects?.find(score => score.subject_id === subject.id);
               
               return (
                 <div
@@ -125,7 +125,7 @@ const SelectSubjectPage: React.FC<SelectSubjectPageProps> = ({ officerId }) => {
                       className={`test-status ${isCompleted ? 'completed' : 'available'}`}
                     >
                       {isCompleted 
-                        ? `Đã hoàn thành (Điểm: ${submission?.score || 0})` 
+                        ? `Đã hoàn thành (Điểm: ${subjectScore?.score || 0})` 
                         : 'Có thể thi'
                       }
                     </span>
@@ -136,7 +136,7 @@ const SelectSubjectPage: React.FC<SelectSubjectPageProps> = ({ officerId }) => {
           </div>
         )}
         
-        {officer?.list_submission && officer.list_submission.length > 0 && (
+        {officer?.subjects && officer.subjects.length > 0 && (
           <div style={{ marginTop: '30px' }}>
             <h4>Lịch sử thi</h4>
             <table className="leaderboard-table">
@@ -148,11 +148,11 @@ const SelectSubjectPage: React.FC<SelectSubjectPageProps> = ({ officerId }) => {
                 </tr>
               </thead>
               <tbody>
-                {officer.list_submission.map((submission, index) => (
-                  <tr key={index}>
-                    <td>{submission.subject_name}</td>
-                    <td>{submission.score}</td>
-                    <td>{new Date(submission.submitted_at * 1000).toLocaleString('vi-VN')}</td>
+                {officer.subjects.map((subjectScore) => (
+                  <tr key={subjectScore.subject_id}>
+                    <td>{subjectScore.subject_name}</td>
+                    <td>{subjectScore.score}</td>
+                    <td>{new Date(subjectScore.submitted_at * 1000).toLocaleString('vi-VN')}</td>
                   </tr>
                 ))}
               </tbody>
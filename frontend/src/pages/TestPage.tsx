@@ -1,8 +1,13 @@
-import React, { useState, useEffect, useCallback } from 'react';
+import React, { useState, useEffect, useCallback, useRef } from 'react';
 import { useParams, useNavigate } from 'react-router-dom';
-import { getOfficerSubjectTest, startTest, submitTest } from '../api/api';
+import { getHeartbeat, getOfficerSubjectTest, getTestQuestions, saveAnswers, startTest, submitTest } from '../api/api';
 import { Test, TestAnswers, TestPageProps } from '../types/api';
 
+// newIdempotencyKey identifies one save or submit, so a retried request is
+// not applied twice
+const newIdempotencyKey = (testId: string): string =>
+  `${testId}-${Date.now()}-${Math.random().toString(36).slice(2)}`;
+
 const TestPage: React.FC<TestPageProps> = ({ officerId }) => {
   const { subjectId } = useParams<{ subjectId: string }>();
   const navigate = useNavigate();
@@ -14,14 +19,36 @@ const TestPage: React.FC<TestPageProps> = ({ officerId }) => {
   const [submitting, setSubmitting] = useState<boolean>(false);
   const [error, setError] = useState<string>('');
   const [testStarted, setTestStarted] = useState<boolean>(false);
+  // Set while a proctor has paused the timer
+  const [paused, setPaused] = useState<boolean>(false);
+  // Reused when a submit is retried with the same answers
+  const submitKey = useRef<string | null>(null);
+  // Autosave sends one request at a time. Answers changed meanwhile wait in
+  // pendingSaves and go out together, so an older save never lands last.
+  const pendingSaves = useRef<TestAnswers>({});
+  const saveInFlight = useRef<boolean>(false);
 
   const fetchTest = useCallback(async (): Promise<void> => {
     if (!subjectId) return;
     
     try {
       setLoading(true);
-      const testData = await getOfficerSubjectTest(officerId, subjectId);
+      let testData = await getOfficerSubjectTest(subjectId);
+      // Questions are only sent for a started test
+      if (testData.start_time && testData.start_time > 0 && !testData.is_finished) {
+        testData = await getTestQuestions(testData.id);
+      }
       setTest(testData);
+      setPaused(!!testData.paused);
+
+      // Resume with the answers saved before a refresh
+      const saved: TestAnswers = {};
+      Object.entries(testData.saved_answers || {}).forEach(([questionId, savedAnswer]) => {
+        if (savedAnswer.answer) {
+          saved[questionId] = savedAnswer.answer;
+        }
+      });
+      setAnswers(saved);
       
       // Check if test has already started
       if (testData.start_time && testData.start_time > 0) {
@@ -43,7 +70,7 @@ const TestPage: React.FC<TestPageProps> = ({ officerId }) => {
   const handleSubmit = useCallback(async (): Promise<void> => {
     if (submitting || !test) return;
     
-    const unansweredQuestions = test.questions.filter(q => !answers[q.id]);
+    const unansweredQuestions = (test.questions || []).filter(q => !answers[q.id]);
     if (unansweredQuestions.length > 0) {
       const confirm = window.confirm(
         `Bạn còn ${unansweredQuestions.length} câu hỏi chưa trả lời. Bạn có muốn nộp bài không?`
@@ -53,7 +80,10 @@ const TestPage: React.FC<TestPageProps> = ({ officerId }) => {
 
     try {
       setSubmitting(true);
-      const submission = await submitTest(officerId, test.id, answers);
+      if (!submitKey.current) {
+        submitKey.current = newIdempotencyKey(test.id);
+      }
+      const submission = await submitTest(test.id, answers, submitKey.current);
       
       // Store submission data for result page
       localStorage.setItem('lastSubmission', JSON.stringify(submission));
@@ -68,7 +98,7 @@ const TestPage: React.FC<TestPageProps> = ({ officerId }) => {
 
   // Timer countdown
   useEffect(() => {
-    if (!testStarted || remainingTime <= 0) return;
+    if (!testStarted || paused || remainingTime <= 0) return;
 
     const timer = setInterval(() => {
       setRemainingTime(prev => {
@@ -82,14 +112,30 @@ const TestPage: React.FC<TestPageProps> = ({ officerId }) => {
     }, 1000);
 
     return () => clearInterval(timer);
-  }, [testStarted, remainingTime, handleSubmit]);
+  }, [testStarted, paused, remainingTime, handleSubmit]);
+
+  // Correct the countdown against the server clock
+  useEffect(() => {
+    if (!testStarted || !test) return;
+
+    const heartbeat = setInterval(() => {
+      getHeartbeat(test.id)
+        .then(clock => {
+          setPaused(!!clock.paused);
+          setRemainingTime(clock.remaining_time);
+        })
+        .catch(err => console.error('Error fetching heartbeat:', err));
+    }, 30000);
+
+    return () => clearInterval(heartbeat);
+  }, [testStarted, test]);
 
   const handleStartTest = async (): Promise<void> => {
     if (!test) return;
     
     try {
       setLoading(true);
-      const startedTest = await startTest(officerId, test.id);
+      const startedTest = await startTest(test.id);
       setTest(startedTest);
       setTestStarted(true);
       setRemainingTime(startedTest.remaining_time || 0);
@@ -101,11 +147,39 @@ const TestPage: React.FC<TestPageProps> = ({ officerId }) => {
     }
   };
 
+  const flushSaves = useCallback(async (testId: string): Promise<void> => {
+    if (saveInFlight.current) return;
+    saveInFlight.current = true;
+    try {
+      while (Object.keys(pendingSaves.current).length > 0) {
+        const batch = pendingSaves.current;
+        pendingSaves.current = {};
+        try {
+          await saveAnswers(testId, batch, newIdempotencyKey(testId));
+        } catch (err) {
+          // Keep the answers not changed since for the next save, and they
+          // are sent again on submit anyway
+          pendingSaves.current = { ...batch, ...pendingSaves.current };
+          console.error('Error saving answers:', err);
+          return;
+        }
+      }
+    } finally {
+      saveInFlight.current = false;
+    }
+  }, []);
+
   const handleAnswerChange = (questionId: number, answer: string): void => {
+    submitKey.current = null;
     setAnswers(prev => ({
       ...prev,
       [questionId]: answer
     }));
+    // Autosave, the latest answer of a question replaces one not sent yet
+    if (test) {
+      pendingSaves.current = { ...pendingSaves.current, [questionId]: answer };
+      flushSaves(test.id);
+    }
   };
 
   const formatTime = (seconds: number): string => {
@@ -155,7 +229,10 @@ const TestPage: React.FC<TestPageProps> = ({ officerId }) => {
         <div className="card" style={{ textAlign: 'center' }}>
           <h2>Bài thi {test.subject?.name}</h2>
           <p>Thời gian: {test.duration ? Math.floor(test.duration / 60) : 0} phút</p>
-          <p>Số câu hỏi: {test.questions?.length || 0}</p>
+          {!!test.extra_time && (
+            <p>Đã bao gồm {Math.floor(test.extra_time / 60)} phút cộng thêm</p>
+          )}
+          <p>Số câu hỏi: {test.num_questions || 0}</p>
           <p>Cán bộ: {test.officer?.name}</p>
           
           <div style={{ margin: '30px 0' }}>
@@ -181,13 +258,13 @@ const TestPage: React.FC<TestPageProps> = ({ officerId }) => {
       {/* Timer */}
       {remainingTime > 0 && (
         <div className="timer">
-          Thời gian còn lại: {formatTime(remainingTime)}
+          Thời gian còn lại: {formatTime(remainingTime)}{paused && ' (tạm dừng)'}
         </div>
       )}
 
       <div className="card">
         <h2>Bài thi {test.subject?.name}</h2>
-        <p>Cán bộ: {test.officer?.name} | Số câu hỏi: {test.questions?.length || 0}</p>
+        <p>Cán bộ: {test.officer?.name} | Số câu hỏi: {test.num_questions || 0}</p>
       </div>
 
       {/* Questions */}
@@ -225,7 +302,7 @@ const TestPage: React.FC<TestPageProps> = ({ officerId }) => {
       <div className="card" style={{ textAlign: 'center' }}>
         <div style={{ marginBottom: '20px' }}>
           <p>
-            Đã trả lời: {Object.keys(answers).length} / {test.questions?.length || 0} câu hỏi
+            Đã trả lời: {Object.keys(answers).length} / {test.num_questions || 0} câu hỏi
           </p>
         </div>
         
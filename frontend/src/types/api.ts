@@ -13,7 +13,16 @@ export interface Officer {
   rank: string;
   score: number;
   unit: string;
-  list_submission: Submission[];
+  subjects?: SubjectScore[];
+}
+
+// Score an officer keeps in a subject; submissions are admin-only
+export interface SubjectScore {
+  subject_id: number;
+  subject_name: string;
+  score: number;
+  attempts: number;
+  submitted_at: number;
 }
 
 export interface Question {
@@ -23,7 +32,6 @@ export interface Question {
   answer_b: string;
   answer_c: string;
   answer_d: string;
-  correct: string; // A, B, C, or D
 }
 
 export interface Chapter {
@@ -48,27 +56,39 @@ export interface Subject {
 }
 
 export interface Test {
-  id: number;
+  id: string;
   name: string;
   contest_id: string;
   duration: number; // in seconds
+  extra_time?: number; // seconds of duration granted by an accommodation
   start_time: number; // timestamp
   remaining_time: number; // in seconds
   is_finished: boolean;
   officer: Officer;
   subject: Subject;
-  questions: Question[];
+  num_questions: number;
+  paused?: boolean; // an admin paused the timer
+  questions?: Question[]; // only sent once the test is started
+  saved_answers?: Record<string, SavedAnswer>; // answers saved before submission
+}
+
+export interface SavedAnswer {
+  answer: string;
+  saved_at: number; // timestamp
 }
 
 export interface Submission {
-  id: number;
+  id: string;
   officer_id: number;
-  test_id: number;
+  test_id: string;
   subject_id: number;
   subject_name: string;
   score: number;
   submitted_at: number; // timestamp
-  answers: Record<string, string>; // question ID to answer mapping
+  answers: Record<string, string>; // question ID to answer mapping, letters as displayed
+  canonical_answers?: Record<string, string>; // same answers with the question bank's letters
+  voided_at?: number; // an admin voided the submission, its score does not count
+  void_reason?: string;
 }
 
 // API Response Wrapper Types
@@ -104,9 +124,48 @@ export interface SubmissionResponse extends BaseResponse {
   data: Submission;
 }
 
+export interface LoginData {
+  token: string;
+  expires_at: number; // timestamp
+  officer: Officer;
+}
+
+export interface LoginResponse extends BaseResponse {
+  data: LoginData;
+}
+
+export interface TestClock {
+  server_time: number; // Unix seconds
+  server_time_ms: number;
+  test_id?: string;
+  start_time?: number;
+  deadline?: number;
+  remaining_time: number; // in seconds
+  grace_period?: number;
+  is_finished?: boolean;
+  paused?: boolean; // an admin paused the timer
+}
+
+export interface ClockResponse extends BaseResponse {
+  data: TestClock;
+}
+
+export interface SavedAnswersResponse extends BaseResponse {
+  data: Record<string, SavedAnswer>;
+}
+
 // API Error Types
 export interface ApiError {
-  [key: string]: string;
+  code: string;
+  message: string;
+  message_vi: string;
+  request_id?: string;
+  details?: unknown;
+}
+
+export interface ApiErrorResponse {
+  error: ApiError;
+  status: 'error';
 }
 
 // Component Props Types